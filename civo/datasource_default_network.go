@@ -0,0 +1,85 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to get the default network of a region
+func dataSourceDefaultNetwork() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Retrieve information about the default network of a region for use in other resources.",
+			"An error will be raised if the region doesn't have a default network.",
+		}, "\n\n"),
+		ReadContext: dataSourceDefaultNetworkRead,
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region of the default network, if is not declared the region of the provider is used",
+			},
+			// Computed resource
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the default network",
+			},
+			"label": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The label of the default network",
+			},
+			"cidr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The CIDR block of the default network",
+			},
+		},
+	}
+}
+
+func dataSourceDefaultNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
+	}
+
+	log.Printf("[INFO] Getting the default network")
+	network, err := getDefaultNetwork(apiClient)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive the default network", err)
+	}
+
+	d.SetId(network.ID)
+	d.Set("name", network.Name)
+	d.Set("label", network.Label)
+	d.Set("cidr", network.CIDR)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
+
+	return nil
+}
+
+// getDefaultNetwork returns the default network of the region of the client,
+// civogo only returns a generic error if the region doesn't have one
+func getDefaultNetwork(apiClient *civogo.Client) (*civogo.Network, error) {
+	network, err := apiClient.GetDefaultNetwork()
+	if err != nil {
+		if err.Error() == "no default network found" {
+			return nil, fmt.Errorf("the region %s doesn't have a default network, create a civo_network and pass its ID instead", apiClient.Region)
+		}
+		return nil, err
+	}
+
+	return network, nil
+}
@@ -0,0 +1,36 @@
+package civo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoDefaultNetwork_basic(t *testing.T) {
+	datasourceName := "data.civo_default_network.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoDefaultNetworkConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(datasourceName, "id"),
+					resource.TestCheckResourceAttrSet(datasourceName, "label"),
+					resource.TestCheckResourceAttrSet(datasourceName, "cidr"),
+					resource.TestCheckResourceAttr(datasourceName, "region", testAccRegion()),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCivoDefaultNetworkConfig() string {
+	return fmt.Sprintf(`
+data "civo_default_network" "foobar" {
+	region = "%s"
+}
+`, testAccRegion())
+}
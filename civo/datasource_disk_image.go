@@ -3,7 +3,6 @@ package civo
 import (
 	"fmt"
 
-	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/datalist"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -39,7 +38,7 @@ func dataSourceDiskImage() *schema.Resource {
 }
 
 func getDiskimages(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	region, ok := extra["region"].(string)
@@ -49,6 +48,9 @@ func getDiskimages(m interface{}, extra map[string]interface{}) ([]interface{},
 
 	if region != "" {
 		apiClient.Region = region
+		if err := validateRegion(apiClient, region); err != nil {
+			return nil, fmt.Errorf("[ERR] %s", err)
+		}
 	}
 
 	templateDiskList := []TemplateDisk{}
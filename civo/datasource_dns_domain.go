@@ -0,0 +1,130 @@
+package civo
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceDNSDomain data source to get from the api a domain by name
+// together with all the records of the domain
+func dataSourceDNSDomain() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Get information on a domain and its records. This data source provides the id of the domain and the list of its records, so records can be added to a domain managed elsewhere without hardcoding its id.",
+			"An error will be raised if the provided domain name is not in your Civo account.",
+		}, "\n\n"),
+		ReadContext: dataSourceDNSDomainRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The name of the domain, it must match exactly the name in your Civo account",
+			},
+			// Computed resource
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The records of the domain, sorted by name, type and value",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the record",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the record",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The record type, one of A, CNAME, MX, SRV or TXT",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address (A or MX), hostname (CNAME or MX) or text value (TXT) served for this record",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The priority of the record",
+						},
+						"ttl": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "How long caching DNS servers should cache this record",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDNSDomainRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*providerMeta).Client()
+	name := d.Get("name").(string)
+
+	log.Printf("[INFO] Getting the domain %s", name)
+	domain, err := apiClient.GetDNSDomain(name)
+	if err != nil {
+		if err == civogo.ErrDNSDomainNotFound || utils.IsNotFoundError(err) {
+			return diag.Errorf("[ERR] the domain %s was not found in your Civo account", name)
+		}
+		return utils.DiagError("[ERR] failed to retrive domain", err)
+	}
+
+	log.Printf("[INFO] Getting the records of the domain %s", domain.ID)
+	records, err := apiClient.ListDNSRecords(domain.ID)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive the records of the domain", err)
+	}
+
+	d.SetId(domain.ID)
+	d.Set("name", domain.Name)
+	if err := d.Set("records", flattenDNSRecords(records)); err != nil {
+		return diag.Errorf("[ERR] error setting records: %s", err)
+	}
+
+	return nil
+}
+
+// flattenDNSRecords sorts the records so the list doesn't change
+// between reads when the API returns them in another order
+func flattenDNSRecords(records []civogo.DNSRecord) []interface{} {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].Value < records[j].Value
+	})
+
+	flattened := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		flattened = append(flattened, map[string]interface{}{
+			"id":       record.ID,
+			"name":     record.Name,
+			"type":     string(record.Type),
+			"value":    record.Value,
+			"priority": record.Priority,
+			"ttl":      record.TTL,
+		})
+	}
+
+	return flattened
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -39,7 +40,7 @@ func dataSourceDNSDomainName() *schema.Resource {
 }
 
 func dataSourceDNSDomainNameRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	var foundDomain *civogo.DNSDomain
 
@@ -47,7 +48,7 @@ func dataSourceDNSDomainNameRead(ctx context.Context, d *schema.ResourceData, m
 		log.Printf("[INFO] Getting the domain by id")
 		domain, err := apiClient.FindDNSDomain(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive domain: %s", err)
+			return utils.DiagError("[ERR] failed to retrive domain", err)
 		}
 
 		foundDomain = domain
@@ -55,7 +56,7 @@ func dataSourceDNSDomainNameRead(ctx context.Context, d *schema.ResourceData, m
 		log.Printf("[INFO] Getting the domain by name")
 		image, err := apiClient.FindDNSDomain(name.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive domain: %s", err)
+			return utils.DiagError("[ERR] failed to retrive domain", err)
 		}
 
 		foundDomain = image
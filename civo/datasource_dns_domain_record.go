@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -74,18 +75,18 @@ func dataSourceDNSDomainRecord() *schema.Resource {
 }
 
 func dataSourceDNSDomainRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 	domain := d.Get("domain_id").(string)
 	name := d.Get("name").(string)
 
 	allRecords, err := apiClient.ListDNSRecords(domain)
 	if err != nil {
-		return diag.Errorf("error retrieving all domain records: %s", err)
+		return utils.DiagError("error retrieving all domain records", err)
 	}
 
 	record, err := getRecordByName(allRecords, name)
 	if err != nil {
-		return diag.Errorf("Error retrieving records by name: %s", err)
+		return utils.DiagError("Error retrieving records by name", err)
 	}
 
 	d.SetId(record.ID)
@@ -0,0 +1,114 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoDNSDomain_basic(t *testing.T) {
+	datasourceName := "data.civo_dns_domain.domain"
+	domain := acctest.RandomWithPrefix("domain") + ".com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoDNSDomainConfig(domain),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(datasourceName, "id", "civo_dns_domain_name.domain", "id"),
+					resource.TestCheckResourceAttr(datasourceName, "records.#", "1"),
+					resource.TestCheckResourceAttr(datasourceName, "records.0.name", "www"),
+					resource.TestCheckResourceAttr(datasourceName, "records.0.value", "10.10.10.1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCivoDNSDomainConfig(domain string) string {
+	return fmt.Sprintf(`
+resource "civo_dns_domain_name" "domain" {
+	name = "%[1]s"
+}
+
+resource "civo_dns_domain_record" "www" {
+	domain_id = civo_dns_domain_name.domain.id
+	type = "A"
+	name = "www"
+	value = "10.10.10.1"
+	ttl = 600
+}
+
+data "civo_dns_domain" "domain" {
+	name = civo_dns_domain_name.domain.name
+	depends_on = [civo_dns_domain_record.www]
+}
+`, domain)
+}
+
+func TestDataSourceDNSDomainRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/dns":
+			fmt.Fprint(rw, `[{"id": "12345", "name": "example.com"}, {"id": "67890", "name": "sub.example.com"}]`)
+		case "/v2/dns/12345/records":
+			fmt.Fprint(rw, `[
+				{"id": "c", "domain_id": "12345", "name": "www", "type": "A", "value": "10.0.0.2", "ttl": 600},
+				{"id": "a", "domain_id": "12345", "name": "@", "type": "MX", "value": "mail.example.com", "priority": 10, "ttl": 3600},
+				{"id": "b", "domain_id": "12345", "name": "www", "type": "A", "value": "10.0.0.1", "ttl": 600}
+			]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := dataSourceDNSDomain().TestResourceData()
+	d.Set("name", "example.com")
+
+	if diags := dataSourceDNSDomainRead(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "12345" {
+		t.Fatalf("expected the domain 12345, got %s", d.Id())
+	}
+
+	records := d.Get("records").([]interface{})
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		record := records[i].(map[string]interface{})
+		if record["id"].(string) != id {
+			t.Fatalf("expected the record %d to be %s, got %s", i, id, record["id"].(string))
+		}
+	}
+	if mx := records[0].(map[string]interface{}); mx["type"].(string) != "MX" || mx["priority"].(int) != 10 {
+		t.Fatalf("unexpected MX record: %v", mx)
+	}
+
+	d = dataSourceDNSDomain().TestResourceData()
+	d.Set("name", "missing.com")
+
+	diags := dataSourceDNSDomainRead(context.Background(), d, &providerMeta{client: client})
+	if !diags.HasError() {
+		t.Fatal("expected an error for a domain that doesn't exist")
+	}
+	if !strings.Contains(diags[0].Summary, "the domain missing.com was not found in your Civo account") {
+		t.Fatalf("unexpected error message: %s", diags[0].Summary)
+	}
+}
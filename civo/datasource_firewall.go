@@ -2,10 +2,12 @@ package civo
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"strings"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -18,7 +20,7 @@ func dataSourceFirewall() *schema.Resource {
 		Description: strings.Join([]string{
 			"Retrieve information about a firewall for use in other resources.",
 			"This data source provides all of the firewall's properties as configured on your Civo account.",
-			"Firewalls may be looked up by id or name, and you can optionally pass region if you want to make a lookup for an expecific firewall inside that region.",
+			"Firewalls may be looked up by id or name, and you can optionally pass region if you want to make a lookup for an expecific firewall inside that region. If region is omitted, only the default region of the provider is searched.",
 		}, "\n\n"),
 		ReadContext: dataSourceFirewallRead,
 		Schema: map[string]*schema.Schema{
@@ -39,7 +41,7 @@ func dataSourceFirewall() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.NoZeroValues,
-				Description:  "The region where the firewall is",
+				Description:  "The region where the firewall is, if is not declared only the default region of the provider is searched, the region is validated so an invalid region fails with an error instead of a not found",
 			},
 			// Computed resource
 			"network_id": {
@@ -47,16 +49,19 @@ func dataSourceFirewall() *schema.Resource {
 				Computed:    true,
 				Description: "The id of the associated network",
 			},
+			"config_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A JSON document with the firewall and all its rules, with a stable ordering so it can be diffed",
+			},
 		},
 	}
 }
 
 func dataSourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
 	var foundFirewall *civogo.Firewall
@@ -65,7 +70,7 @@ func dataSourceFirewallRead(ctx context.Context, d *schema.ResourceData, m inter
 		log.Printf("[INFO] Getting the firewall by id")
 		firewall, err := apiClient.FindFirewall(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive firewall: %s", err)
+			return dataSourceLookupError(apiClient, "firewall", id.(string), err)
 		}
 
 		foundFirewall = firewall
@@ -73,7 +78,7 @@ func dataSourceFirewallRead(ctx context.Context, d *schema.ResourceData, m inter
 		log.Printf("[INFO] Getting the firewall by name")
 		firewall, err := apiClient.FindFirewall(name.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive firewall: %s", err)
+			return dataSourceLookupError(apiClient, "firewall", name.(string), err)
 		}
 
 		foundFirewall = firewall
@@ -84,5 +89,73 @@ func dataSourceFirewallRead(ctx context.Context, d *schema.ResourceData, m inter
 	d.Set("network_id", foundFirewall.NetworkID)
 	d.Set("region", apiClient.Region)
 
+	log.Printf("[INFO] Getting the rules of the firewall %s", foundFirewall.ID)
+	rules, err := apiClient.ListFirewallRules(foundFirewall.ID)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive firewall rules", err)
+	}
+
+	configJSON, err := firewallConfigJSON(foundFirewall, rules, apiClient.Region)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to serialize the firewall config: %s", err)
+	}
+	d.Set("config_json", configJSON)
+
 	return nil
 }
+
+// firewallConfig is the document used by config_json, the order of the
+// fields here is the order they will have in the output
+type firewallConfig struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Region    string               `json:"region"`
+	NetworkID string               `json:"network_id"`
+	Rules     []firewallRuleConfig `json:"rules"`
+}
+
+type firewallRuleConfig struct {
+	ID        string   `json:"id"`
+	Label     string   `json:"label"`
+	Direction string   `json:"direction"`
+	Action    string   `json:"action"`
+	Protocol  string   `json:"protocol"`
+	StartPort string   `json:"start_port"`
+	EndPort   string   `json:"end_port"`
+	Cidr      []string `json:"cidr"`
+}
+
+// firewallConfigJSON serialize the firewall and the rules, the rules and the
+// cidr of every rule are sorted, so the output is always the same
+func firewallConfigJSON(firewall *civogo.Firewall, rules []civogo.FirewallRule, region string) (string, error) {
+	config := firewallConfig{
+		ID:        firewall.ID,
+		Name:      firewall.Name,
+		Region:    region,
+		NetworkID: firewall.NetworkID,
+		Rules:     make([]firewallRuleConfig, 0, len(rules)),
+	}
+
+	sorted := append([]civogo.FirewallRule{}, rules...)
+	utils.SortFirewallRules(sorted)
+
+	for _, rule := range sorted {
+		config.Rules = append(config.Rules, firewallRuleConfig{
+			ID:        rule.ID,
+			Label:     rule.Label,
+			Direction: rule.Direction,
+			Action:    rule.Action,
+			Protocol:  rule.Protocol,
+			StartPort: rule.StartPort,
+			EndPort:   rule.EndPort,
+			Cidr:      rule.Cidr,
+		})
+	}
+
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
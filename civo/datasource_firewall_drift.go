@@ -0,0 +1,249 @@
+package civo
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to compare the live rules of a firewall with an expected
+// ruleset, to detect the drift of the firewall outside of a plan
+func dataSourceFirewallDrift() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Compare the live rules of a firewall with an expected ruleset.",
+			"The rules are compared in a normalized form, so the order of the rules and of the cidr, the label and a single port written as a range don't count as a difference.",
+			"The data source never changes the firewall, it only reports if the firewall is in sync, the live rules that are not expected and the expected rules that are missing.",
+		}, "\n\n"),
+		ReadContext: dataSourceFirewallDriftRead,
+		Schema: map[string]*schema.Schema{
+			"firewall_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The ID of the firewall to check",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region of the firewall, if is not declared the region of the provider is used",
+			},
+			"expected_rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The rules the firewall should have, without any expected rule all the rules of the firewall are extra",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "A label for the rule, it is not compared",
+						},
+						"protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "tcp",
+							ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "icmp"}, false),
+							Description:  "The protocol choice from `tcp`, `udp` or `icmp` (the default if unspecified is `tcp`)",
+						},
+						"start_port": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The start of the port range of the rule (or the single port if required)",
+						},
+						"end_port": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The end of the port range (this is optional, by default it is the single port in start_port)",
+						},
+						"cidr": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "The CIDR notation of the other end to affect",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"direction": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"ingress", "egress"}, false),
+							Description:  "The direction of the rule can be ingress or egress",
+						},
+						"action": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+							Description:  "The action of the rule can be allow or deny",
+						},
+					},
+				},
+			},
+			// Computed resource
+			"in_sync": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the live rules of the firewall are the same as the expected rules",
+			},
+			"extra": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The live rules of the firewall that are not expected",
+				Elem:        firewallDriftRuleSchema(true),
+			},
+			"missing": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The expected rules that are not in the firewall",
+				Elem:        firewallDriftRuleSchema(false),
+			},
+		},
+	}
+}
+
+// firewallDriftRuleSchema is the schema of the extra and missing rules, only
+// the live rules have an ID
+func firewallDriftRuleSchema(withID bool) *schema.Resource {
+	rule := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The label of the rule",
+			},
+			"protocol": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The protocol of the rule",
+			},
+			"start_port": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The start port of the rule",
+			},
+			"end_port": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The end port of the rule",
+			},
+			"cidr": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The CIDR of the rule",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"direction": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The direction of the rule",
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The action of the rule",
+			},
+		},
+	}
+
+	if withID {
+		rule.Schema["id"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID of the rule",
+		}
+	}
+
+	return rule
+}
+
+func dataSourceFirewallDriftRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
+	}
+
+	expected := []civogo.FirewallRule{}
+	for i, raw := range d.Get("expected_rule").([]interface{}) {
+		tfRule := raw.(map[string]interface{})
+		if err := validateTemplateRule(tfRule); err != nil {
+			return diag.Errorf("[ERR] the expected_rule %d is not valid: %s", i, err)
+		}
+
+		cidr := []string{}
+		for _, value := range tfRule["cidr"].([]interface{}) {
+			cidr = append(cidr, value.(string))
+		}
+
+		expected = append(expected, civogo.FirewallRule{
+			Label:     tfRule["label"].(string),
+			Protocol:  tfRule["protocol"].(string),
+			StartPort: tfRule["start_port"].(string),
+			EndPort:   tfRule["end_port"].(string),
+			Cidr:      cidr,
+			Direction: tfRule["direction"].(string),
+			Action:    tfRule["action"].(string),
+		})
+	}
+
+	firewallID := d.Get("firewall_id").(string)
+	log.Printf("[INFO] Getting the firewall %s", firewallID)
+	firewall, err := apiClient.FindFirewall(firewallID)
+	if err != nil {
+		return dataSourceLookupError(apiClient, "firewall", firewallID, err)
+	}
+
+	log.Printf("[INFO] Getting the rules of the firewall %s", firewall.ID)
+	live, err := apiClient.ListFirewallRules(firewall.ID)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive firewall rules", err)
+	}
+
+	extra, missing := utils.DiffFirewallRules(live, expected)
+
+	d.SetId(firewall.ID)
+	d.Set("region", apiClient.Region)
+	d.Set("in_sync", len(extra) == 0 && len(missing) == 0)
+
+	if err := d.Set("extra", flattenFirewallDriftRules(extra, true)); err != nil {
+		return diag.Errorf("[ERR] error setting the extra rules of the firewall %s: %s", firewall.ID, err)
+	}
+	if err := d.Set("missing", flattenFirewallDriftRules(missing, false)); err != nil {
+		return diag.Errorf("[ERR] error setting the missing rules of the firewall %s: %s", firewall.ID, err)
+	}
+
+	return nil
+}
+
+// flattenFirewallDriftRules flatten the rules for the extra and missing lists
+func flattenFirewallDriftRules(rules []civogo.FirewallRule, withID bool) []interface{} {
+	flattened := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		cidr := make([]interface{}, 0, len(rule.Cidr))
+		for _, value := range rule.Cidr {
+			cidr = append(cidr, value)
+		}
+
+		flattenedRule := map[string]interface{}{
+			"label":      rule.Label,
+			"protocol":   rule.Protocol,
+			"start_port": rule.StartPort,
+			"end_port":   rule.EndPort,
+			"cidr":       cidr,
+			"direction":  rule.Direction,
+			"action":     rule.Action,
+		}
+		if withID {
+			flattenedRule["id"] = rule.ID
+		}
+
+		flattened = append(flattened, flattenedRule)
+	}
+
+	return flattened
+}
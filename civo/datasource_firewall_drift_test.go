@@ -0,0 +1,114 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoFirewallDrift_basic(t *testing.T) {
+	datasourceName := "data.civo_firewall_drift.foobar"
+	name := acctest.RandomWithPrefix("fw-drift")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoFirewallDriftConfig(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "in_sync", "false"),
+					resource.TestCheckResourceAttr(datasourceName, "extra.#", "0"),
+					resource.TestCheckResourceAttr(datasourceName, "missing.#", "1"),
+					resource.TestCheckResourceAttr(datasourceName, "missing.0.start_port", "443"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceFirewallDriftRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/firewalls":
+			fmt.Fprint(rw, `[{"id": "12345", "name": "web"}]`)
+		case "/v2/firewalls/12345/rules":
+			fmt.Fprint(rw, `[
+				{"id": "a", "protocol": "tcp", "start_port": "22", "end_port": "22", "direction": "ingress", "action": "allow", "cidr": ["0.0.0.0/0"]},
+				{"id": "b", "protocol": "tcp", "start_port": "3306", "end_port": "3306", "direction": "ingress", "action": "allow", "cidr": ["0.0.0.0/0"]}
+			]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := dataSourceFirewallDrift().TestResourceData()
+	d.Set("firewall_id", "12345")
+	d.Set("expected_rule", []interface{}{
+		map[string]interface{}{"label": "ssh", "protocol": "tcp", "start_port": "22", "end_port": "", "cidr": []interface{}{"0.0.0.0/0"}, "direction": "ingress", "action": "allow"},
+		map[string]interface{}{"label": "https", "protocol": "tcp", "start_port": "443", "end_port": "", "cidr": []interface{}{"0.0.0.0/0"}, "direction": "ingress", "action": "allow"},
+	})
+
+	if diags := dataSourceFirewallDriftRead(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Get("in_sync").(bool) {
+		t.Fatal("expected the firewall not to be in sync")
+	}
+	if got := d.Get("extra.#").(int); got != 1 || d.Get("extra.0.id").(string) != "b" {
+		t.Fatalf("expected the rule b to be extra, got %d extra rules", got)
+	}
+	if got := d.Get("missing.#").(int); got != 1 || d.Get("missing.0.label").(string) != "https" {
+		t.Fatalf("expected the https rule to be missing, got %d missing rules", got)
+	}
+}
+
+func testAccDataSourceCivoFirewallDriftConfig(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+	create_default_rules = false
+}
+
+resource "civo_firewall_rule" "ssh" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "tcp"
+	start_port = "22"
+	end_port = "22"
+	cidr = ["192.168.1.2/32"]
+	direction = "ingress"
+	action = "allow"
+}
+
+data "civo_firewall_drift" "foobar" {
+	firewall_id = civo_firewall_rule.ssh.firewall_id
+
+	expected_rule {
+		start_port = "22"
+		cidr = ["192.168.1.2"]
+		direction = "ingress"
+		action = "allow"
+	}
+
+	expected_rule {
+		start_port = "443"
+		cidr = ["0.0.0.0/0"]
+		direction = "ingress"
+		action = "allow"
+	}
+}
+`, name)
+}
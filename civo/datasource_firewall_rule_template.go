@@ -0,0 +1,255 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// firewallRuleTemplate is a named set of rules declared in the provider
+type firewallRuleTemplate struct {
+	Name  string
+	Rules []map[string]interface{}
+}
+
+// ruleTemplateSchema is the schema of the rule_template block of the provider
+func ruleTemplateSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Named sets of firewall rules that can be expanded with the `civo_firewall_rule_template` data source, to reuse the same rules across many firewalls. The templates are validated when the provider is configured.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+					Description:  "The name of the template, it must be unique",
+				},
+				"rule": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Description: "The rules of the template",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"label": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "A string that will be the displayed name/reference for this rule",
+							},
+							"protocol": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      "tcp",
+								ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "icmp"}, false),
+								Description:  "The protocol choice from `tcp`, `udp` or `icmp` (the default if unspecified is `tcp`)",
+							},
+							"start_port": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The start of the port range to configure for this rule (or the single port if required)",
+							},
+							"end_port": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The end of the port range (this is optional, by default it will only apply to the single port listed in start_port)",
+							},
+							"cidr": {
+								Type:        schema.TypeList,
+								Required:    true,
+								MinItems:    1,
+								Description: "The CIDR notation of the other end to affect",
+								Elem:        &schema.Schema{Type: schema.TypeString},
+							},
+							"direction": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"ingress", "egress"}, false),
+								Description:  "The direction of the rule can be ingress or egress",
+							},
+							"action": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+								Description:  "The action of the rule can be allow or deny",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// expandRuleTemplates build the templates from the provider configuration,
+// it returns an error if a template is duplicated or a rule is not valid
+func expandRuleTemplates(list []interface{}) (map[string]*firewallRuleTemplate, error) {
+	templates := make(map[string]*firewallRuleTemplate, len(list))
+
+	for _, raw := range list {
+		tfTemplate := raw.(map[string]interface{})
+		name := tfTemplate["name"].(string)
+		if _, ok := templates[name]; ok {
+			return nil, fmt.Errorf("[ERR] the rule_template %q is declared more than once", name)
+		}
+
+		template := &firewallRuleTemplate{Name: name}
+		for i, rawRule := range tfTemplate["rule"].([]interface{}) {
+			tfRule := rawRule.(map[string]interface{})
+			if err := validateTemplateRule(tfRule); err != nil {
+				return nil, fmt.Errorf("[ERR] the rule %d of the rule_template %q is not valid: %s", i, name, err)
+			}
+
+			cidr := []interface{}{}
+			cidr = append(cidr, tfRule["cidr"].([]interface{})...)
+
+			template.Rules = append(template.Rules, map[string]interface{}{
+				"label":      tfRule["label"].(string),
+				"protocol":   tfRule["protocol"].(string),
+				"start_port": tfRule["start_port"].(string),
+				"end_port":   tfRule["end_port"].(string),
+				"cidr":       cidr,
+				"direction":  tfRule["direction"].(string),
+				"action":     tfRule["action"].(string),
+			})
+		}
+
+		templates[name] = template
+	}
+
+	return templates, nil
+}
+
+// validateTemplateRule check the ports and the cidr of a rule of a template
+func validateTemplateRule(rule map[string]interface{}) error {
+	startPort := rule["start_port"].(string)
+	endPort := rule["end_port"].(string)
+
+	if rule["protocol"].(string) != "icmp" && startPort == "" {
+		return fmt.Errorf("start_port is required for the %s protocol", rule["protocol"].(string))
+	}
+
+	var start int
+	if startPort != "" {
+		port, err := strconv.Atoi(startPort)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("start_port %q is not a valid port", startPort)
+		}
+		start = port
+	}
+
+	if endPort != "" {
+		port, err := strconv.Atoi(endPort)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("end_port %q is not a valid port", endPort)
+		}
+		if port < start {
+			return fmt.Errorf("end_port %s is lower than start_port %s", endPort, startPort)
+		}
+	}
+
+	for _, cidr := range rule["cidr"].([]interface{}) {
+		value, _ := cidr.(string)
+		if _, _, err := net.ParseCIDR(value); err != nil {
+			return fmt.Errorf("cidr %q is not a valid CIDR", value)
+		}
+	}
+
+	return nil
+}
+
+// Data source to expand a rule template of the provider into a list of rules
+func dataSourceFirewallRuleTemplate() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Expand a `rule_template` declared in the provider into a list of rules.",
+			"The rules can be used with `for_each` in `civo_firewall_rule` to create the same rules in many firewalls.",
+		}, "\n\n"),
+		ReadContext: dataSourceFirewallRuleTemplateRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the rule_template declared in the provider",
+			},
+			// Computed resource
+			"rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The rules of the template",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The label of the rule",
+						},
+						"protocol": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The protocol of the rule",
+						},
+						"start_port": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The start port of the rule",
+						},
+						"end_port": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The end port of the rule",
+						},
+						"cidr": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The CIDR of the rule",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"direction": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The direction of the rule",
+						},
+						"action": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The action of the rule",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFirewallRuleTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	template, ok := m.(*providerMeta).ruleTemplates[name]
+	if !ok {
+		return diag.Errorf("[ERR] the rule_template %q is not declared in the provider", name)
+	}
+
+	log.Printf("[INFO] expanding the rule_template %s", name)
+
+	rules := make([]interface{}, 0, len(template.Rules))
+	for _, rule := range template.Rules {
+		rules = append(rules, rule)
+	}
+
+	d.SetId(name)
+	if err := d.Set("rules", rules); err != nil {
+		return diag.Errorf("[ERR] error setting the rules of the rule_template %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package civo
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoFirewallRuleTemplate_basic(t *testing.T) {
+	datasourceName := "data.civo_firewall_rule_template.web"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoFirewallRuleTemplateConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "rules.#", "2"),
+					resource.TestCheckResourceAttr(datasourceName, "rules.0.start_port", "80"),
+					resource.TestCheckResourceAttr(datasourceName, "rules.0.protocol", "tcp"),
+					resource.TestCheckResourceAttr(datasourceName, "rules.1.start_port", "443"),
+					resource.TestCheckResourceAttr(datasourceName, "rules.1.cidr.0", "0.0.0.0/0"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandRuleTemplates(t *testing.T) {
+	rule := func(startPort, endPort, cidr string) map[string]interface{} {
+		return map[string]interface{}{
+			"label":      "",
+			"protocol":   "tcp",
+			"start_port": startPort,
+			"end_port":   endPort,
+			"cidr":       []interface{}{cidr},
+			"direction":  "ingress",
+			"action":     "allow",
+		}
+	}
+	template := func(name string, rules ...interface{}) interface{} {
+		return map[string]interface{}{"name": name, "rule": rules}
+	}
+
+	templates, err := expandRuleTemplates([]interface{}{
+		template("ssh", rule("22", "", "0.0.0.0/0")),
+		template("web", rule("80", "", "0.0.0.0/0"), rule("443", "", "::/0")),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(templates) != 2 || len(templates["web"].Rules) != 2 {
+		t.Fatalf("expected 2 templates with 2 web rules, got %+v", templates)
+	}
+
+	invalid := map[string][]interface{}{
+		"duplicated name": {template("ssh", rule("22", "", "0.0.0.0/0")), template("ssh", rule("2222", "", "0.0.0.0/0"))},
+		"invalid cidr":    {template("ssh", rule("22", "", "0.0.0.0"))},
+		"invalid port":    {template("ssh", rule("ssh", "", "0.0.0.0/0"))},
+		"missing port":    {template("ssh", rule("", "", "0.0.0.0/0"))},
+		"reversed range":  {template("web", rule("443", "80", "0.0.0.0/0"))},
+	}
+	for name, list := range invalid {
+		if _, err := expandRuleTemplates(list); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func testAccDataSourceCivoFirewallRuleTemplateConfig() string {
+	return `
+provider "civo" {
+	rule_template {
+		name = "web"
+
+		rule {
+			label      = "http"
+			start_port = "80"
+			cidr       = ["0.0.0.0/0"]
+			direction  = "ingress"
+			action     = "allow"
+		}
+
+		rule {
+			label      = "https"
+			start_port = "443"
+			cidr       = ["0.0.0.0/0"]
+			direction  = "ingress"
+			action     = "allow"
+		}
+	}
+}
+
+data "civo_firewall_rule_template" "web" {
+	name = "web"
+}
+`
+}
@@ -2,8 +2,10 @@ package civo
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/civo/civogo"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -20,12 +22,35 @@ func TestAccDataSourceCivoFirewall_basic(t *testing.T) {
 				Config: testAccDataSourceCivoFirewallConfig(name),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(datasourceName, "name", name),
+					resource.TestCheckResourceAttrSet(datasourceName, "config_json"),
 				),
 			},
 		},
 	})
 }
 
+func TestDataSourceCivoFirewallConfigJSON_stable(t *testing.T) {
+	firewall := &civogo.Firewall{ID: "fw-1", Name: "web", NetworkID: "net-1"}
+	rules := []civogo.FirewallRule{
+		{ID: "b", Protocol: "tcp", StartPort: "443", EndPort: "443", Cidr: []string{"10.0.0.0/8", "0.0.0.0/0"}, Direction: "ingress", Action: "allow"},
+		{ID: "a", Protocol: "tcp", StartPort: "80", EndPort: "80", Cidr: []string{"0.0.0.0/0"}, Direction: "ingress", Action: "allow"},
+	}
+	reversed := []civogo.FirewallRule{rules[1], rules[0]}
+
+	first, err := firewallConfigJSON(firewall, rules, "LON1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	second, err := firewallConfigJSON(firewall, reversed, "LON1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if first != second {
+		t.Fatalf("expected the same output for the same rules, got:\n%s\n%s", first, second)
+	}
+}
+
 func testAccDataSourceCivoFirewallConfig(name string) string {
 	return fmt.Sprintf(`
 resource "civo_firewall" "foobar" {
@@ -37,3 +62,21 @@ data "civo_firewall" "foobar" {
 }
 `, name)
 }
+
+func TestAccDataSourceCivoFirewall_invalidRegion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "civo_firewall" "foobar" {
+	name = "default"
+	region = "NOT-A-REGION"
+}
+`,
+				ExpectError: regexp.MustCompile("invalid region NOT-A-REGION"),
+			},
+		},
+	})
+}
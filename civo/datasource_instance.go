@@ -38,7 +38,7 @@ func dataSourceInstance() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.NoZeroValues,
-				Description:  "The region of an existing Instance",
+				Description:  "The region of an existing Instance, the region is validated so an invalid region fails with an error instead of a not found",
 			},
 			// computed attributes
 			"reverse_dns": {
@@ -110,6 +110,7 @@ func dataSourceInstance() *schema.Resource {
 			"initial_password": {
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 				Description: "Instance initial password",
 			},
 			"private_ip": {
@@ -142,11 +143,9 @@ func dataSourceInstance() *schema.Resource {
 }
 
 func dataSourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
 	var foundImage *civogo.Instance
@@ -155,7 +154,7 @@ func dataSourceInstanceRead(ctx context.Context, d *schema.ResourceData, m inter
 		log.Printf("[INFO] Getting the instance by id")
 		image, err := apiClient.FindInstance(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive instance: %s", err)
+			return dataSourceLookupError(apiClient, "instance", id.(string), err)
 		}
 
 		foundImage = image
@@ -163,7 +162,7 @@ func dataSourceInstanceRead(ctx context.Context, d *schema.ResourceData, m inter
 		log.Printf("[INFO] Getting the instance by hostname")
 		image, err := apiClient.FindInstance(hostname.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive instance: %s", err)
+			return dataSourceLookupError(apiClient, "instance", hostname.(string), err)
 		}
 
 		foundImage = image
@@ -0,0 +1,138 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to check if a specific instance exists
+// using the id or the hostname, without failing if is not found
+func dataSourceInstanceMaybe() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Check if an instance exists for use in other resources. Unlike `civo_instance`, this data source does not raise an error when the instance is not found, instead `exists` is set to `false`.",
+			"Note: The ID or the hostname must match exactly, an instance with a hostname that only contains it is not found. When specifying a hostname, an error will still be raised if more than one instances found.",
+		}, "\n\n"),
+		ReadContext: dataSourceInstanceMaybeRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"id", "hostname"},
+			},
+			"hostname": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"id", "hostname"},
+				Description:  "The hostname of the Instance",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region of the Instance",
+			},
+			// computed attributes
+			"exists": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the instance exists",
+			},
+			"private_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The private ip of the instance, empty if the instance doesn't exist",
+			},
+			"public_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The public ip of the instance, empty if the instance doesn't exist",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the instance, empty if the instance doesn't exist",
+			},
+		},
+	}
+}
+
+func dataSourceInstanceMaybeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
+	}
+
+	var searchBy string
+
+	if id, ok := d.GetOk("id"); ok {
+		log.Printf("[INFO] Getting the instance by id")
+		searchBy = id.(string)
+	} else if hostname, ok := d.GetOk("hostname"); ok {
+		log.Printf("[INFO] Getting the instance by hostname")
+		searchBy = hostname.(string)
+	}
+
+	d.Set("region", apiClient.Region)
+
+	instances, err := apiClient.ListAllInstances()
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive instance", err)
+	}
+
+	instance, err := findInstanceExactMatch(instances, searchBy)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive instance", err)
+	}
+	if instance == nil {
+		log.Printf("[INFO] instance %s not found", searchBy)
+		d.SetId(searchBy)
+		d.Set("exists", false)
+		d.Set("private_ip", "")
+		d.Set("public_ip", "")
+		d.Set("status", "")
+		return nil
+	}
+
+	d.SetId(instance.ID)
+	d.Set("hostname", instance.Hostname)
+	d.Set("exists", true)
+	d.Set("private_ip", instance.PrivateIP)
+	d.Set("public_ip", instance.PublicIP)
+	d.Set("status", instance.Status)
+
+	return nil
+}
+
+// findInstanceExactMatch returns the instance with exactly the ID or the
+// hostname, or nil if there is none. civogo also matches a part of them, so
+// another instance with a similar hostname would be found instead
+func findInstanceExactMatch(instances []civogo.Instance, search string) (*civogo.Instance, error) {
+	var found *civogo.Instance
+	for i, instance := range instances {
+		if instance.ID == search {
+			return &instances[i], nil
+		}
+		if instance.Hostname != search {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: there are many instances with the hostname %s", civogo.MultipleMatchesError, search)
+		}
+		found = &instances[i]
+	}
+
+	return found, nil
+}
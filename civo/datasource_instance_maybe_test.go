@@ -0,0 +1,93 @@
+package civo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoInstanceMaybe_basic(t *testing.T) {
+	datasourceName := "data.civo_instance_maybe.foobar"
+	name := acctest.RandomWithPrefix("instance-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoInstanceMaybeConfig(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "hostname", name),
+					resource.TestCheckResourceAttr(datasourceName, "exists", "true"),
+					resource.TestCheckResourceAttrSet(datasourceName, "private_ip"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCivoInstanceMaybe_notFound(t *testing.T) {
+	datasourceName := "data.civo_instance_maybe.foobar"
+	name := acctest.RandomWithPrefix("instance-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoInstanceMaybeConfigNotFound(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "exists", "false"),
+					resource.TestCheckResourceAttr(datasourceName, "public_ip", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestFindInstanceExactMatch(t *testing.T) {
+	instances := []civogo.Instance{
+		{ID: "1", Hostname: "backup.example.com"},
+		{ID: "2", Hostname: "web"},
+		{ID: "3", Hostname: "web"},
+	}
+
+	if instance, err := findInstanceExactMatch(instances, "backup"); err != nil || instance != nil {
+		t.Fatalf("expected no instance for a part of the hostname, got %v, %v", instance, err)
+	}
+
+	if instance, err := findInstanceExactMatch(instances, "backup.example.com"); err != nil || instance == nil || instance.ID != "1" {
+		t.Fatalf("expected the instance 1 by hostname, got %v, %v", instance, err)
+	}
+
+	if instance, err := findInstanceExactMatch(instances, "3"); err != nil || instance == nil || instance.ID != "3" {
+		t.Fatalf("expected the instance 3 by ID, got %v, %v", instance, err)
+	}
+
+	if _, err := findInstanceExactMatch(instances, "web"); err == nil {
+		t.Fatal("expected an error when the hostname matches many instances")
+	}
+}
+
+func testAccDataSourceCivoInstanceMaybeConfig(name string) string {
+	return fmt.Sprintf(`
+resource "civo_instance" "vm" {
+	hostname = "%s"
+}
+
+data "civo_instance_maybe" "foobar" {
+	hostname = civo_instance.vm.hostname
+}
+`, name)
+}
+
+func testAccDataSourceCivoInstanceMaybeConfigNotFound(name string) string {
+	return fmt.Sprintf(`
+data "civo_instance_maybe" "foobar" {
+	hostname = "%s"
+}
+`, name)
+}
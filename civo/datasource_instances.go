@@ -34,7 +34,7 @@ func dataSourceInstances() *schema.Resource {
 }
 
 func getDataSourceInstances(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	region, ok := extra["region"].(string)
@@ -44,6 +44,9 @@ func getDataSourceInstances(m interface{}, extra map[string]interface{}) ([]inte
 
 	if region != "" {
 		apiClient.Region = region
+		if err := validateRegion(apiClient, region); err != nil {
+			return nil, fmt.Errorf("[ERR] %s", err)
+		}
 	}
 
 	var instance []interface{}
@@ -164,6 +167,7 @@ func instancesSchema() map[string]*schema.Schema {
 		},
 		"initial_password": {
 			Type:        schema.TypeString,
+			Sensitive:   true,
 			Description: "Initial password of the instance",
 		},
 		"private_ip": {
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/datalist"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -39,7 +38,7 @@ func dataSourceInstancesSize() *schema.Resource {
 }
 
 func getInstancesSizes(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	sizes := []interface{}{}
 	partialSizes, err := apiClient.ListInstanceSizes()
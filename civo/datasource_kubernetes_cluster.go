@@ -230,11 +230,9 @@ func dataSourceApplicationSchema() *schema.Schema {
 }
 
 func dataSourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
 	var foundCluster *civogo.KubernetesCluster
@@ -243,14 +241,14 @@ func dataSourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData
 		log.Printf("[INFO] Getting the kubernetes Cluster by id")
 		kubeCluster, err := apiClient.FindKubernetesCluster(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive kubernetes cluster: %s", err)
+			return dataSourceLookupError(apiClient, "kubernetes cluster", id.(string), err)
 		}
 		foundCluster = kubeCluster
 	} else if name, ok := d.GetOk("name"); ok {
 		log.Printf("[INFO] Getting the kubernetes Cluster by name")
 		kubeCluster, err := apiClient.FindKubernetesCluster(name.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive kubernetes cluster: %s", err)
+			return dataSourceLookupError(apiClient, "kubernetes cluster", name.(string), err)
 		}
 
 		foundCluster = kubeCluster
@@ -0,0 +1,149 @@
+package civo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to wait until all the nodes of a kubernetes cluster are ready
+func dataSourceKubernetesClusterReady() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Wait until all the nodes of a Civo Kubernetes cluster are ready, to sequence the provisioning that needs a ready cluster (e.g. installing applications).",
+			"The data source polls the cluster until the number of ready nodes matches the number of nodes of the pools, or the read timeout (30 minutes by default) elapses. If the timeout elapses, `ready` is `false` instead of failing.",
+		}, "\n\n"),
+		ReadContext: dataSourceKubernetesClusterReadyRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The ID of the Kubernetes cluster",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region of the cluster, if is not declared the region of the provider is used",
+			},
+			// computed attributes
+			"ready": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "When all the nodes of the cluster are ready, this will return `true`",
+			},
+			"ready_nodes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of ready nodes",
+			},
+			"desired_nodes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of nodes of all the pools of the cluster",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the cluster",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func dataSourceKubernetesClusterReadyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
+	}
+	clusterID := d.Get("cluster_id").(string)
+
+	// the read is cancelled when its timeout elapses, so the wait stops a bit
+	// earlier to still have the time to return the nodes as not ready
+	timeout := d.Timeout(schema.TimeoutRead)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	timeout -= timeout / 10
+
+	var cluster *civogo.KubernetesCluster
+	readyStateConf := &resource.StateChangeConf{
+		Pending: []string{"waiting"},
+		Target:  []string{"ready"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetKubernetesCluster(clusterID)
+			if err != nil {
+				return 0, "", err
+			}
+			cluster = resp
+
+			readyNodes, desiredNodes := kubernetesClusterNodes(resp)
+			log.Printf("[DEBUG] the kubernetes cluster %s has %d of %d nodes ready", clusterID, readyNodes, desiredNodes)
+			if resp.Ready && desiredNodes > 0 && readyNodes >= desiredNodes {
+				return resp, "ready", nil
+			}
+			return resp, "waiting", nil
+		},
+		Timeout:    timeout,
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+
+	log.Printf("[INFO] waiting for the nodes of the kubernetes cluster %s to be ready", clusterID)
+	ready := true
+	if _, err := readyStateConf.WaitForStateContext(ctx); err != nil {
+		var timeoutErr *resource.TimeoutError
+		timedOut := errors.As(err, &timeoutErr) || errors.Is(err, context.DeadlineExceeded)
+		if !timedOut || cluster == nil {
+			return utils.DiagError("[ERR] failed to wait for the kubernetes cluster", err)
+		}
+		log.Printf("[WARN] the nodes of the kubernetes cluster %s were not ready before the timeout", clusterID)
+		ready = false
+	}
+
+	readyNodes, desiredNodes := kubernetesClusterNodes(cluster)
+
+	d.SetId(cluster.ID)
+	d.Set("ready", ready)
+	d.Set("ready_nodes", readyNodes)
+	d.Set("desired_nodes", desiredNodes)
+	d.Set("status", cluster.Status)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
+
+	return nil
+}
+
+// kubernetesClusterNodes returns the number of active nodes of the cluster
+// and the number of nodes of all its pools
+func kubernetesClusterNodes(cluster *civogo.KubernetesCluster) (int, int) {
+	desiredNodes := 0
+	for _, pool := range cluster.Pools {
+		desiredNodes += pool.Count
+	}
+	if desiredNodes == 0 {
+		desiredNodes = cluster.NumTargetNode
+	}
+
+	readyNodes := 0
+	for _, instance := range cluster.Instances {
+		if instance.Status == "ACTIVE" {
+			readyNodes++
+		}
+	}
+
+	return readyNodes, desiredNodes
+}
@@ -0,0 +1,98 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoKubernetesClusterReady_basic(t *testing.T) {
+	datasourceName := "data.civo_kubernetes_cluster_ready.foobar"
+	var kubernetesClusterName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoKubernetesClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoKubernetesClusterReadyConfig(kubernetesClusterName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "ready", "true"),
+					resource.TestCheckResourceAttr(datasourceName, "desired_nodes", "2"),
+					resource.TestCheckResourceAttr(datasourceName, "ready_nodes", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestKubernetesClusterNodes(t *testing.T) {
+	cluster := &civogo.KubernetesCluster{
+		NumTargetNode: 3,
+		Pools:         []civogo.KubernetesPool{{Count: 2}, {Count: 1}},
+		Instances: []civogo.KubernetesInstance{
+			{Status: "ACTIVE"},
+			{Status: "ACTIVE"},
+			{Status: "BUILDING"},
+		},
+	}
+
+	readyNodes, desiredNodes := kubernetesClusterNodes(cluster)
+	if readyNodes != 2 || desiredNodes != 3 {
+		t.Fatalf("expected 2 of 3 nodes ready, got %d of %d", readyNodes, desiredNodes)
+	}
+}
+
+func TestDataSourceKubernetesClusterReadyRead_notReady(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, `{"id": "cluster", "status": "ACTIVE", "ready": true, "pools": [{"count": 2}], "instances": [{"status": "ACTIVE"}, {"status": "BUILDING"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	d := dataSourceKubernetesClusterReady().TestResourceData()
+	d.Set("cluster_id", "cluster")
+
+	// the read is cancelled at its deadline, like the SDK does at the read timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	if diags := dataSourceKubernetesClusterReadyRead(ctx, d, &providerMeta{client: client}); diags.HasError() {
+		t.Fatalf("expected the cluster to be returned as not ready, got %v", diags)
+	}
+	if d.Get("ready").(bool) {
+		t.Fatal("expected the cluster not to be ready")
+	}
+	if d.Get("ready_nodes").(int) != 1 || d.Get("desired_nodes").(int) != 2 {
+		t.Fatalf("expected 1 of 2 nodes ready, got %d of %d", d.Get("ready_nodes"), d.Get("desired_nodes"))
+	}
+}
+
+func testAccDataSourceCivoKubernetesClusterReadyConfig(name string) string {
+	return fmt.Sprintf(`
+resource "civo_kubernetes_cluster" "foobar" {
+	name = "%s"
+	pools {
+		size = "g4s.kube.medium"
+		node_count = 2
+	}
+}
+
+data "civo_kubernetes_cluster_ready" "foobar" {
+	cluster_id = civo_kubernetes_cluster.foobar.id
+}
+`, name)
+}
@@ -24,7 +24,7 @@ func dataSourceKubernetesVersion() *schema.Resource {
 }
 
 func getKubernetesVersions(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	versions := []interface{}{}
 	partialVersions, err := apiClient.ListAvailableKubernetesVersions()
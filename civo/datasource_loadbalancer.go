@@ -2,12 +2,14 @@ package civo
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	"github.com/civo/civogo"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Data source to get from the api a specific Load Balancer
@@ -16,19 +18,25 @@ func dataSourceLoadBalancer() *schema.Resource {
 	return &schema.Resource{
 		Description: strings.Join([]string{
 			"Get information on a load balancer for use in other resources. This data source provides all of the load balancers properties as configured on your Civo account.",
-			"An error will be raised if the provided load balancer name does not exist in your Civo account.",
+			"An error will be raised if the provided load balancer name does not exist in your Civo account, or if more than one load balancer has that name.",
 		}, "\n\n"),
 		ReadContext: dataSourceLoadBalancerRead,
 		Schema: map[string]*schema.Schema{
 			"id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The id of the load balancer to retrieve (You can find this id from service annotations 'kubernetes.civo.com/loadbalancer-id')",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "The id of the load balancer to retrieve (You can find this id from service annotations 'kubernetes.civo.com/loadbalancer-id')",
 			},
 			"name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The name of the load balancer (You can find this name from service annotations 'kubernetes.civo.com/loadbalancer-name')",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "The name of the load balancer (You can find this name from service annotations 'kubernetes.civo.com/loadbalancer-name')",
 			},
 			"region": {
 				Type:        schema.TypeString,
@@ -88,7 +96,7 @@ func dataSourceLoadBalancer() *schema.Resource {
 			"backends": {
 				Type:        schema.TypeList,
 				Computed:    true,
-				Description: "",
+				Description: "The backends of the load balancer",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"ip": {
@@ -124,26 +132,29 @@ func dataSourceLoadBalancer() *schema.Resource {
 }
 
 func dataSourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
-	var searchBy string
+	var lb *civogo.LoadBalancer
 
-	if name, ok := d.GetOk("name"); ok {
-		log.Printf("[INFO] Getting the LoadBalancer by name")
-		searchBy = name.(string)
-	} else if id, ok := d.GetOk("id"); ok {
+	if id, ok := d.GetOk("id"); ok {
 		log.Printf("[INFO] Getting the LoadBalancer by id")
-		searchBy = id.(string)
-	}
+		loadBalancer, err := apiClient.GetLoadBalancer(id.(string))
+		if err != nil {
+			return dataSourceLookupError(apiClient, "load balancer", id.(string), err)
+		}
 
-	lb, err := apiClient.FindLoadBalancer(searchBy)
-	if err != nil {
-		return diag.Errorf("[ERR] failed to retrive LoadBalancer: %s", err)
+		lb = loadBalancer
+	} else if name, ok := d.GetOk("name"); ok {
+		log.Printf("[INFO] Getting the LoadBalancer by name")
+		loadBalancer, err := findLoadBalancerByName(apiClient, name.(string))
+		if err != nil {
+			return dataSourceLookupError(apiClient, "load balancer", name.(string), err)
+		}
+
+		lb = loadBalancer
 	}
 
 	d.SetId(lb.ID)
@@ -166,6 +177,32 @@ func dataSourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m i
 	return nil
 }
 
+// findLoadBalancerByName search the load balancer with exactly that name,
+// civogo also match part of the name, so we can't use FindLoadBalancer
+func findLoadBalancerByName(apiClient *civogo.Client, name string) (*civogo.LoadBalancer, error) {
+	lbs, err := apiClient.ListLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []civogo.LoadBalancer
+	for _, lb := range lbs {
+		if lb.Name == name {
+			found = append(found, lb)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("unable to find the load balancer %s, zero matches", name)
+	}
+
+	if len(found) > 1 {
+		return nil, fmt.Errorf("unable to find the load balancer %s because there were %d load balancers with that name, use the id instead", name, len(found))
+	}
+
+	return &found[0], nil
+}
+
 // function to flatten the load balancer backend when is coming from the api
 func flattenLoadBalancerBackend(backend []civogo.LoadBalancerBackend) []interface{} {
 	if backend == nil {
@@ -2,6 +2,7 @@ package civo
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -33,6 +34,21 @@ func TestAccDataSourceCivoLoadBalancer_basic(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceCivoLoadBalancer_notFound(t *testing.T) {
+	name := acctest.RandomWithPrefix("lb-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceCivoLoadBalancerConfigName(name),
+				ExpectError: regexp.MustCompile("zero matches"),
+			},
+		},
+	})
+}
+
 func testAccDataSourceCivoLoadBalancerConfig(name string) string {
 	return fmt.Sprintf(`
 resource "civo_instance" "vm" {
@@ -62,3 +78,11 @@ data "civo_loadbalancer" "foobar" {
 }
 `, name, name)
 }
+
+func testAccDataSourceCivoLoadBalancerConfigName(name string) string {
+	return fmt.Sprintf(`
+data "civo_loadbalancer" "foobar" {
+	name = "%s"
+}
+`, name)
+}
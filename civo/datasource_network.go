@@ -40,7 +40,7 @@ func dataSourceNetwork() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validation.NoZeroValues,
 				AtLeastOneOf: []string{"id", "label", "region"},
-				Description:  "The region of an existing network",
+				Description:  "The region of an existing network, the region is validated so an invalid region fails with an error instead of a not found",
 			},
 			// Computed resource
 			"name": {
@@ -58,11 +58,9 @@ func dataSourceNetwork() *schema.Resource {
 }
 
 func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
 	var foundNetwork *civogo.Network
@@ -71,7 +69,7 @@ func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interf
 		log.Printf("[INFO] Getting the network by id")
 		network, err := apiClient.FindNetwork(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive network: %s", err)
+			return dataSourceLookupError(apiClient, "network", id.(string), err)
 		}
 
 		foundNetwork = network
@@ -79,7 +77,7 @@ func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interf
 		log.Printf("[INFO] Getting the network by label")
 		network, err := apiClient.FindNetwork(label.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive network: %s", err)
+			return dataSourceLookupError(apiClient, "network", label.(string), err)
 		}
 
 		foundNetwork = network
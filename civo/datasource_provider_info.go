@@ -0,0 +1,95 @@
+package civo
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderVersion is the version of the provider, it is set at build time with
+// -ldflags "-X github.com/civo/terraform-provider-civo/civo.ProviderVersion=x.y.z"
+// and if it is not set the version of the module in the binary is used
+var ProviderVersion = ""
+
+// civoAPIVersion is the version of the Civo API in the paths used by civogo,
+// the API doesn't negotiate the version, it is part of every path
+const civoAPIVersion = "v2"
+
+const (
+	providerModulePath = "github.com/civo/terraform-provider-civo"
+	civogoModulePath   = "github.com/civo/civogo"
+)
+
+// Data source to return the versions in use, to report issues, the read
+// doesn't call the API
+func dataSourceProviderInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Get the versions of the provider, of the civogo library and of the Civo API used by the provider, to include them when reporting an issue.",
+			"The data source doesn't call the API and has no side effects.",
+		}, "\n\n"),
+		ReadContext: dataSourceProviderInfoRead,
+		Schema: map[string]*schema.Schema{
+			"provider_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the provider, or `dev` for a build that is not from a release",
+			},
+			"civogo_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the civogo library in the provider",
+			},
+			"api_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the Civo API used by civogo, the API doesn't negotiate the version, it is the version in the path of the requests",
+			},
+		},
+	}
+}
+
+func dataSourceProviderInfoRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	info, _ := debug.ReadBuildInfo()
+
+	providerVersion := ProviderVersion
+	if providerVersion == "" {
+		providerVersion = moduleVersion(info, providerModulePath, "dev")
+	}
+
+	d.SetId(providerVersion)
+	d.Set("provider_version", providerVersion)
+	d.Set("civogo_version", moduleVersion(info, civogoModulePath, civogo.Version))
+	d.Set("api_version", civoAPIVersion)
+
+	return nil
+}
+
+// moduleVersion returns the version of the module in the build info, or the
+// fallback if the binary has no build info or the version is unknown, like
+// in a build from a local checkout
+func moduleVersion(info *debug.BuildInfo, path string, fallback string) string {
+	if info == nil {
+		return fallback
+	}
+
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, module := range modules {
+		if module == nil || module.Path != path {
+			continue
+		}
+		if module.Replace != nil {
+			module = module.Replace
+		}
+		if module.Version == "" || module.Version == "(devel)" {
+			return fallback
+		}
+		return strings.TrimPrefix(module.Version, "v")
+	}
+
+	return fallback
+}
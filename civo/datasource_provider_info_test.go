@@ -0,0 +1,56 @@
+package civo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoProviderInfo_basic(t *testing.T) {
+	datasourceName := "data.civo_provider_info.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "civo_provider_info" "foobar" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(datasourceName, "provider_version"),
+					resource.TestCheckResourceAttrSet(datasourceName, "civogo_version"),
+					resource.TestCheckResourceAttr(datasourceName, "api_version", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestModuleVersion(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: providerModulePath, Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: civogoModulePath, Version: "v0.2.74"},
+			{Path: "github.com/example/replaced", Version: "v1.0.0", Replace: &debug.Module{Path: "../replaced"}},
+		},
+	}
+
+	cases := []struct {
+		path, expected string
+	}{
+		{civogoModulePath, "0.2.74"},
+		{providerModulePath, "fallback"},
+		{"github.com/example/replaced", "fallback"},
+		{"github.com/example/missing", "fallback"},
+	}
+
+	for _, c := range cases {
+		if got := moduleVersion(info, c.path, "fallback"); got != c.expected {
+			t.Errorf("moduleVersion(%q): expected %s, got %s", c.path, c.expected, got)
+		}
+	}
+
+	if got := moduleVersion(nil, civogoModulePath, "fallback"); got != "fallback" {
+		t.Errorf("expected the fallback without build info, got %s", got)
+	}
+}
@@ -2,9 +2,12 @@ package civo
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/datalist"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -45,7 +48,7 @@ func dataSourceRegion() *schema.Resource {
 }
 
 func getRegios(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	regions := []interface{}{}
 	partialRegions, err := apiClient.ListRegions()
@@ -72,3 +75,53 @@ func flattenRegions(region, m interface{}, extra map[string]interface{}) (map[st
 
 	return flattenedRegion, nil
 }
+
+// dataSourceRegionClient returns a client for the region of the data source, or the
+// provider region if is not declared, without changing the region of the shared client.
+// The declared region is validated first, so an invalid region is not reported as
+// a resource not found in the region
+func dataSourceRegionClient(d *schema.ResourceData, m interface{}) (*civogo.Client, diag.Diagnostics) {
+	region, _ := d.Get("region").(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+	if region == "" {
+		return apiClient, nil
+	}
+
+	if err := validateRegion(apiClient, region); err != nil {
+		return nil, diag.Errorf("[ERR] %s", err)
+	}
+
+	return apiClient, nil
+}
+
+// validateRegion check the region is one of the regions of Civo
+func validateRegion(apiClient *civogo.Client, region string) error {
+	regions, err := apiClient.ListRegions()
+	if err != nil {
+		return fmt.Errorf("failed to list the regions to validate the region %s: %s", region, err)
+	}
+
+	codes := make([]string, 0, len(regions))
+	for _, r := range regions {
+		if utils.NormalizeRegion(r.Code) == utils.NormalizeRegion(region) {
+			return nil
+		}
+		codes = append(codes, r.Code)
+	}
+
+	return fmt.Errorf("invalid region %s, the available regions are: %s", region, strings.Join(codes, ", "))
+}
+
+// dataSourceLookupError returns the diagnostic for a failed lookup of a data source,
+// when the resource doesn't exist it says in which region it was searched
+func dataSourceLookupError(apiClient *civogo.Client, kind string, search string, err error) diag.Diagnostics {
+	if !utils.IsNotFoundError(err) {
+		return utils.DiagError("[ERR] failed to retrive "+kind, err)
+	}
+
+	region := utils.NormalizeRegion(apiClient.Region)
+	if region == "" {
+		region = "default"
+	}
+	return diag.Errorf("[ERR] the %s %s was not found in the %s region", kind, search, region)
+}
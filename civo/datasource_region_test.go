@@ -0,0 +1,46 @@
+package civo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+)
+
+func TestValidateRegion(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/regions": `[{"code": "LON1", "name": "London 1"}, {"code": "NYC1", "name": "New York 1"}]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	if err := validateRegion(client, "lon1"); err != nil {
+		t.Fatalf("expected lon1 to be a valid region, got %s", err)
+	}
+
+	// a partial match is not a valid region
+	err = validateRegion(client, "LON")
+	if err == nil {
+		t.Fatal("expected LON to be an invalid region")
+	}
+	if !strings.Contains(err.Error(), "LON1, NYC1") {
+		t.Fatalf("expected the error to list the available regions, got %s", err)
+	}
+}
+
+func TestDataSourceLookupError(t *testing.T) {
+	client := &civogo.Client{Region: "lon1"}
+
+	diags := dataSourceLookupError(client, "firewall", "web", fmt.Errorf("ZeroMatchesError: unable to find web, zero matches"))
+	if !diags.HasError() || diags[0].Summary != "[ERR] the firewall web was not found in the LON1 region" {
+		t.Fatalf("expected a not found error in the region, got %v", diags)
+	}
+
+	diags = dataSourceLookupError(client, "firewall", "web", fmt.Errorf("connection refused"))
+	if !diags.HasError() || strings.Contains(diags[0].Summary, "was not found") {
+		t.Fatalf("expected a lookup error, got %v", diags)
+	}
+}
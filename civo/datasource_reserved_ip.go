@@ -0,0 +1,111 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to get from the api a specific reserved IP
+// using the name or the address
+func dataSourceReservedIP() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Get information on an existing reserved IP, to attach it to an instance or to adopt it in other resources.",
+			"Reserved IPs may be looked up by name or ip, and you can optionally pass region if you want to make a lookup for a reserved IP inside that region.",
+		}, "\n\n"),
+		ReadContext: dataSourceReservedIPRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"name", "ip"},
+				Description:  "The name of the reserved IP",
+			},
+			"ip": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPAddress,
+				ExactlyOneOf: []string{"name", "ip"},
+				Description:  "The address of the reserved IP",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region of the reserved IP, if is not declared the region of the provider is used",
+			},
+			// Computed resource
+			"assigned_to_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the resource the reserved IP is assigned to, empty if it is not assigned",
+			},
+			"assigned_to_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the resource the reserved IP is assigned to (e.g. `instance`), empty if it is not assigned",
+			},
+		},
+	}
+}
+
+func dataSourceReservedIPRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
+	}
+
+	log.Printf("[INFO] Getting the reserved ips")
+	ips, err := utils.ListReservedIPs(apiClient)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive reserved ips", err)
+	}
+
+	foundIP, err := lookupReservedIP(ips, d.Get("name").(string), d.Get("ip").(string))
+	if err != nil {
+		return diag.Errorf("[ERR] %s", err)
+	}
+
+	d.SetId(foundIP.ID)
+	d.Set("name", foundIP.Name)
+	d.Set("ip", foundIP.IP)
+	d.Set("region", apiClient.Region)
+	d.Set("assigned_to_id", foundIP.AssignedTo.ID)
+	d.Set("assigned_to_type", foundIP.AssignedTo.Type)
+
+	return nil
+}
+
+// lookupReservedIP return the reserved IP with that name or address,
+// the name is not unique so it fails when more than one reserved IP have it
+func lookupReservedIP(ips []utils.ReservedIP, name string, address string) (*utils.ReservedIP, error) {
+	var found []utils.ReservedIP
+	for _, ip := range ips {
+		if (name != "" && ip.Name == name) || (address != "" && ip.IP == address) {
+			found = append(found, ip)
+		}
+	}
+
+	search := name
+	if search == "" {
+		search = address
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("the reserved ip %s was not found", search)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%d reserved ips match %s, use the ip to select one", len(found), search)
+	}
+}
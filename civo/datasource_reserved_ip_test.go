@@ -0,0 +1,57 @@
+package civo
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoReservedIP_notFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceCivoReservedIPConfig("not-a-reserved-ip"),
+				ExpectError: regexp.MustCompile("the reserved ip not-a-reserved-ip was not found"),
+			},
+		},
+	})
+}
+
+func TestLookupReservedIP(t *testing.T) {
+	ips := []utils.ReservedIP{
+		{ID: "1", Name: "web", IP: "192.0.2.1"},
+		{ID: "2", Name: "db", IP: "192.0.2.2"},
+		{ID: "3", Name: "db", IP: "192.0.2.3"},
+	}
+
+	ip, err := lookupReservedIP(ips, "web", "")
+	if err != nil || ip.ID != "1" {
+		t.Fatalf("expected the reserved ip 1 by name, got %v, %v", ip, err)
+	}
+
+	ip, err = lookupReservedIP(ips, "", "192.0.2.3")
+	if err != nil || ip.ID != "3" {
+		t.Fatalf("expected the reserved ip 3 by address, got %v, %v", ip, err)
+	}
+
+	if _, err := lookupReservedIP(ips, "db", ""); err == nil {
+		t.Fatal("expected an error when the name matches multiple reserved ips")
+	}
+
+	if _, err := lookupReservedIP(ips, "", "192.0.2.4"); err == nil {
+		t.Fatal("expected an error when the reserved ip is not found")
+	}
+}
+
+func testAccDataSourceCivoReservedIPConfig(name string) string {
+	return fmt.Sprintf(`
+data "civo_reserved_ip" "foobar" {
+	name = "%s"
+}
+`, name)
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/datalist"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -24,8 +24,15 @@ type Size struct {
 // use to define the size in resourceInstance
 func dataSourceSize() *schema.Resource {
 	dataListConfig := &datalist.ResourceConfig{
-		Description:         "Retrieves information about the sizes that Civo supports, with the ability to filter the results.",
-		RecordSchema:        SizeSchema(),
+		Description:  "Retrieves information about the sizes that Civo supports, including the kubernetes and database sizes, with the ability to filter the results.",
+		RecordSchema: SizeSchema(),
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "If is used, all sizes will be from this region, if not the region of the provider is used.",
+			},
+		},
 		ResultAttributeName: "sizes",
 		FlattenRecord:       flattenSize,
 		GetRecords:          getSizes,
@@ -36,7 +43,13 @@ func dataSourceSize() *schema.Resource {
 }
 
 func getSizes(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	apiClient := m.(*civogo.Client)
+	region, _ := extra["region"].(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+	if region != "" {
+		if err := validateRegion(apiClient, region); err != nil {
+			return nil, fmt.Errorf("[ERR] %s", err)
+		}
+	}
 
 	sizes := []interface{}{}
 	partialSizes, err := apiClient.ListInstanceSizes()
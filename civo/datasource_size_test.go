@@ -44,6 +44,24 @@ func TestAccDataSourceCivoSize_WithFilterAndSort(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceCivoSize_region(t *testing.T) {
+	datasourceName := "data.civo_size.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoSizeConfigRegion(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDataSourceCivoSizeExist(datasourceName),
+					resource.TestCheckResourceAttr(datasourceName, "sizes.0.type", "kubernetes"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckDataSourceCivoSizeExist(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -134,3 +152,16 @@ data "civo_size" "foobar" {
 }
 `
 }
+
+func testAccDataSourceCivoSizeConfigRegion() string {
+	return fmt.Sprintf(`
+data "civo_size" "foobar" {
+	region = "%s"
+
+	filter {
+		key = "type"
+		values = ["kubernetes"]
+	}
+}
+`, testAccRegion())
+}
@@ -2,21 +2,24 @@ package civo
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Data source to get from the api a specific ssh key
-// using the id or the name
+// using the id, the name or the fingerprint
 func dataSourceSSHKey() *schema.Resource {
 	return &schema.Resource{
 		Description: strings.Join([]string{
 			"Get information on a SSH key. This data source provides the name, and fingerprint as configured on your Civo account.",
+			"SSH keys may be looked up by id, name or fingerprint.",
 			"An error will be raised if the provided SSH key name does not exist in your Civo account.",
 		}, "\n\n"),
 		ReadContext: dataSourceSSHKeyRead,
@@ -25,27 +28,30 @@ func dataSourceSSHKey() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.NoZeroValues,
-				ExactlyOneOf: []string{"id", "name"},
+				ExactlyOneOf: []string{"id", "name", "fingerprint"},
 			},
 			"name": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.NoZeroValues,
-				ExactlyOneOf: []string{"id", "name"},
+				ExactlyOneOf: []string{"id", "name", "fingerprint"},
 				Description:  "The name of the SSH key",
 			},
-			// Computed resource
 			"fingerprint": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The fingerprint of the public key of the SSH key",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"id", "name", "fingerprint"},
+				Description:  "The fingerprint of the public key of the SSH key",
 			},
 		},
 	}
 }
 
 func dataSourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	var searchBy string
 
@@ -57,9 +63,21 @@ func dataSourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interfa
 		searchBy = name.(string)
 	}
 
-	sshKey, err := apiClient.FindSSHKey(searchBy)
-	if err != nil {
-		return diag.Errorf("[ERR] failed to retrive network: %s", err)
+	var sshKey *civogo.SSHKey
+
+	if fingerprint, ok := d.GetOk("fingerprint"); ok {
+		log.Printf("[INFO] Getting the ssh key by fingerprint")
+		key, err := findSSHKeyByFingerprint(apiClient, fingerprint.(string))
+		if err != nil {
+			return utils.DiagError("[ERR] failed to retrive ssh key", err)
+		}
+		sshKey = key
+	} else {
+		key, err := apiClient.FindSSHKey(searchBy)
+		if err != nil {
+			return utils.DiagError("[ERR] failed to retrive network", err)
+		}
+		sshKey = key
 	}
 
 	d.SetId(sshKey.ID)
@@ -68,3 +86,20 @@ func dataSourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	return nil
 }
+
+// findSSHKeyByFingerprint look for the ssh key with exactly the same fingerprint,
+// FindSSHKey only search by id and name
+func findSSHKeyByFingerprint(apiClient *civogo.Client, fingerprint string) (*civogo.SSHKey, error) {
+	sshKeys, err := apiClient.ListSSHKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sshKey := range sshKeys {
+		if sshKey.Fingerprint == fingerprint {
+			return &sshKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find a ssh key with the fingerprint %s", fingerprint)
+}
@@ -35,6 +35,30 @@ func TestAccDataSourceCivoSSHKey_basic(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceCivoSSHKey_fingerprint(t *testing.T) {
+	datasourceName := "data.civo_ssh_key.foobar"
+	name := acctest.RandomWithPrefix("sshkey-test")
+	pubKey, err := testAccGenerateDataSourceCivoSSHKeyPublic()
+	if err != nil {
+		t.Fatalf("Unable to generate public key: %v", err)
+		return
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoSSHKeyConfigFingerprint(name, pubKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "name", name),
+					resource.TestCheckResourceAttrPair(datasourceName, "id", "civo_ssh_key.foobar", "id"),
+				),
+			},
+		},
+	})
+}
+
 func testAccGenerateDataSourceCivoSSHKeyPublic() (string, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
@@ -61,3 +85,16 @@ data "civo_ssh_key" "foobar" {
 }
 `, name, key)
 }
+
+func testAccDataSourceCivoSSHKeyConfigFingerprint(name string, key string) string {
+	return fmt.Sprintf(`
+resource "civo_ssh_key" "foobar" {
+	name = "%s"
+    public_key = "%s"
+}
+
+data "civo_ssh_key" "foobar" {
+	fingerprint = civo_ssh_key.foobar.fingerprint
+}
+`, name, key)
+}
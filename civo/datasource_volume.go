@@ -61,11 +61,9 @@ func dataSourceVolume() *schema.Resource {
 }
 
 func dataSourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
-
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	apiClient, diags := dataSourceRegionClient(d, m)
+	if diags != nil {
+		return diags
 	}
 
 	var foundVolume *civogo.Volume
@@ -74,7 +72,7 @@ func dataSourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interfa
 		log.Printf("[INFO] Getting the volume by id")
 		volume, err := apiClient.FindVolume(id.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive volume: %s", err)
+			return dataSourceLookupError(apiClient, "volume", id.(string), err)
 		}
 
 		foundVolume = volume
@@ -82,7 +80,7 @@ func dataSourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interfa
 		log.Printf("[INFO] Getting the volume by name")
 		volume, err := apiClient.FindVolume(name.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to retrive volume: %s", err)
+			return dataSourceLookupError(apiClient, "volume", name.(string), err)
 		}
 
 		foundVolume = volume
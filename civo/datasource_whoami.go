@@ -0,0 +1,74 @@
+package civo
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Data source to check the credentials of the provider before an apply,
+// it returns the account of the token and the region used by the provider
+func dataSourceWhoami() *schema.Resource {
+	return &schema.Resource{
+		Description: strings.Join([]string{
+			"Verify the credentials of the provider, this data source calls a lightweight authenticated endpoint and returns the account of the token and the region that will be used.",
+			"An error will be raised if the token is not valid or the region doesn't exist, so it can be used as a preflight check in CI pipelines.",
+		}, "\n\n"),
+		ReadContext: dataSourceWhoamiRead,
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The region to check, if is not declared the region of the provider is used, or the default region of the account if the provider doesn't have one",
+			},
+			// computed attributes
+			"account_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the account of the token",
+			},
+			"default_user_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the default user of the account",
+			},
+		},
+	}
+}
+
+func dataSourceWhoamiRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	log.Printf("[INFO] checking the credentials of the provider")
+	quota, err := apiClient.GetQuota()
+	if err != nil {
+		return utils.DiagError("[ERR] failed to verify the credentials of the provider", err)
+	}
+
+	region := apiClient.Region
+	if region == "" {
+		defaultRegion, err := apiClient.GetDefaultRegion()
+		if err != nil {
+			return utils.DiagError("[ERR] failed to retrive the default region", err)
+		}
+		region = defaultRegion.Code
+	} else {
+		if _, err := apiClient.FindRegion(region); err != nil {
+			return utils.DiagError("[ERR] failed to verify the region "+region, err)
+		}
+	}
+
+	d.SetId(quota.ID)
+	d.Set("account_id", quota.ID)
+	d.Set("default_user_id", quota.DefaultUserID)
+	d.Set("region", utils.NormalizeRegion(region))
+
+	return nil
+}
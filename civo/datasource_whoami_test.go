@@ -0,0 +1,32 @@
+package civo
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoWhoami_basic(t *testing.T) {
+	datasourceName := "data.civo_whoami.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCivoWhoamiConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(datasourceName, "account_id"),
+					resource.TestCheckResourceAttrSet(datasourceName, "region"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCivoWhoamiConfig() string {
+	return `
+data "civo_whoami" "foobar" {
+}
+`
+}
@@ -0,0 +1,210 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCivoFirewallRule_importBasic(t *testing.T) {
+	resourceName := "civo_firewall_rule.testrule"
+	var firewalName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigBasic(firewalName),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccFirewallRuleImportID(resourceName),
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "00000000-0000-0000-0000-000000000000:00000000-0000-0000-0000-000000000000",
+				ExpectError:   regexp.MustCompile("was not found in the region"),
+			},
+		},
+	})
+}
+
+func testAccFirewallRuleImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		firewallID := rs.Primary.Attributes["firewall_id"]
+		id := rs.Primary.ID
+
+		return fmt.Sprintf("%s:%s", firewallID, id), nil
+	}
+}
+
+func TestResourceFirewallRuleImport_notFound(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/firewalls/12345/rules": `[{"id": "67890", "protocol": "tcp", "start_port": "22", "direction": "ingress", "action": "allow", "cidr": ["0.0.0.0/0"]}]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	d := resourceFirewallRule().TestResourceData()
+	d.SetId("12345:00000")
+
+	_, err = resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err == nil {
+		t.Fatal("expected an error for a rule that doesn't exist")
+	}
+	if !strings.Contains(err.Error(), "the firewall rule 00000 was not found in the firewall 12345") {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+}
+
+func TestResourceFirewallRuleImport_apiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(`{"code": "database_error", "reason": "internal error"}`))
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceFirewallRule().TestResourceData()
+	d.SetId("12345:67890")
+
+	_, err = resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err == nil {
+		t.Fatal("expected an error when the API fails")
+	}
+	if strings.Contains(err.Error(), "was not found") || !strings.Contains(err.Error(), "internal error") {
+		t.Fatalf("expected the error of the API, got: %s", err)
+	}
+}
+
+func TestResourceFirewallRuleImport_all(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/firewalls/12345/rules": `[
+			{"id": "67890", "protocol": "tcp", "start_port": "22", "direction": "ingress", "action": "allow", "cidr": ["0.0.0.0/0"]},
+			{"id": "67891", "protocol": "tcp", "start_port": "80", "end_port": "443", "direction": "ingress", "action": "allow", "cidr": ["10.0.0.0/8", "1.2.3.4/32"], "label": "web"}
+		]`,
+		"/v2/firewalls/empty/rules": `[]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	d := resourceFirewallRule().TestResourceData()
+	d.SetId("12345:*")
+
+	results, err := resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(results))
+	}
+	if results[0] != d {
+		t.Fatal("expected the first rule to be imported in the original resource data")
+	}
+
+	expected := []map[string]string{
+		{"id": "67890", "start_port": "22", "end_port": "", "label": ""},
+		{"id": "67891", "start_port": "80", "end_port": "443", "label": "web"},
+	}
+	for i, rule := range results {
+		if rule.Id() != expected[i]["id"] {
+			t.Fatalf("expected the rule %d to have the ID %s, got %s", i, expected[i]["id"], rule.Id())
+		}
+		if rule.Get("firewall_id").(string) != "12345" {
+			t.Fatalf("expected the rule %s to be in the firewall 12345, got %s", rule.Id(), rule.Get("firewall_id").(string))
+		}
+		for _, key := range []string{"start_port", "end_port", "label"} {
+			if rule.Get(key).(string) != expected[i][key] {
+				t.Fatalf("expected %s of the rule %s to be %q, got %q", key, rule.Id(), expected[i][key], rule.Get(key).(string))
+			}
+		}
+	}
+
+	d = resourceFirewallRule().TestResourceData()
+	d.SetId("empty:*")
+
+	_, err = resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err == nil {
+		t.Fatal("expected an error for a firewall without rules")
+	}
+	if !strings.Contains(err.Error(), "the firewall empty has no rules to import") {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+}
+
+func TestResourceFirewallRuleImport_otherRegion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		region := req.URL.Query().Get("region")
+		switch {
+		case req.URL.Path == "/v2/regions":
+			fmt.Fprint(rw, `[{"code": "LON1"}, {"code": "NYC1"}, {"code": "FRA1"}]`)
+		case req.URL.Path == "/v2/firewalls/12345/rules" && region == "NYC1":
+			fmt.Fprint(rw, `[{"id": "67890", "protocol": "tcp", "start_port": "22", "direction": "ingress", "action": "allow", "cidr": ["0.0.0.0/0"]}]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"code": "database_firewall_not_found", "reason": "firewall not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	d := resourceFirewallRule().TestResourceData()
+	d.SetId("12345:67890")
+
+	results, err := resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 1 || results[0].Id() != "67890" {
+		t.Fatalf("expected the rule 67890 to be imported, got %v", results)
+	}
+	if region := results[0].Get("region").(string); region != "NYC1" {
+		t.Fatalf("expected the region NYC1 where the firewall was found, got %s", region)
+	}
+	if client.Region != "LON1" {
+		t.Fatalf("expected the region of the provider client to be kept, got %s", client.Region)
+	}
+
+	d = resourceFirewallRule().TestResourceData()
+	d.SetId("00000:*")
+
+	_, err = resourceFirewallRuleImport(context.Background(), d, &providerMeta{client: client})
+	if err == nil {
+		t.Fatal("expected an error for a firewall that doesn't exist in any region")
+	}
+	if !strings.Contains(err.Error(), "the firewall 00000 was not found in the region LON1 or in the other 2 regions searched") {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+}
@@ -0,0 +1,116 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCivoFirewall_importWithRules(t *testing.T) {
+	firewallName := acctest.RandomWithPrefix("tf-fw-import")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallConfigImportWithRules(firewallName),
+			},
+			{
+				// the firewall is imported only with its ID, the region is found by the import
+				ResourceName:      "civo_firewall.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "civo_firewall_rule.ssh",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccFirewallRuleImportID("civo_firewall_rule.ssh"),
+			},
+			{
+				// the imported state matches the configuration
+				Config:   testAccCheckCivoFirewallConfigImportWithRules(firewallName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCivoFirewallConfigImportWithRules(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "ssh" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "tcp"
+	start_port = "22"
+	end_port = "22"
+	cidr = ["192.168.1.2/32"]
+	direction = "ingress"
+	action = "allow"
+	label = "ssh"
+}
+`, name)
+}
+
+func TestResourceFirewallImport_otherRegion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		region := req.URL.Query().Get("region")
+		switch {
+		case req.URL.Path == "/v2/regions":
+			fmt.Fprint(rw, `[{"code": "LON1"}, {"code": "NYC1"}]`)
+		case req.URL.Path == "/v2/firewalls/12345/rules" && region == "NYC1":
+			fmt.Fprint(rw, `[]`)
+		case req.URL.Path == "/v2/firewalls" && region == "NYC1":
+			fmt.Fprint(rw, `[{"id": "12345", "name": "web", "network_id": "net"}]`)
+		case req.URL.Path == "/v2/firewalls":
+			fmt.Fprint(rw, `[]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"code": "database_firewall_not_found", "reason": "firewall not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	d := resourceFirewall().TestResourceData()
+	d.SetId("12345")
+
+	results, err := resourceFirewallImport(context.Background(), d, &providerMeta{client: client})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 1 || results[0].Id() != "12345" {
+		t.Fatalf("expected the firewall 12345 to be imported, got %v", results)
+	}
+	if region := d.Get("region").(string); region != "NYC1" {
+		t.Fatalf("expected the region NYC1 where the firewall was found, got %s", region)
+	}
+	if !d.Get("create_default_rules").(bool) {
+		t.Fatal("expected create_default_rules to be set to its default, or the plan replaces the firewall")
+	}
+
+	d = resourceFirewall().TestResourceData()
+	d.SetId("00000")
+
+	_, err = resourceFirewallImport(context.Background(), d, &providerMeta{client: client})
+	if err == nil || !strings.Contains(err.Error(), "the firewall 00000 was not found in the region LON1 or in the other 1 regions searched") {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCivoVolumeAttachment_importBasic(t *testing.T) {
+	resourceName := "civo_volume_attachment.foobar"
+	var volumeAttachmentName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoVolumeAttachmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoVolumeAttachmentConfigBasic(volumeAttachmentName),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccVolumeAttachmentImportID(resourceName),
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "NOTAREGION:00000000-0000-0000-0000-000000000000:00000000-0000-0000-0000-000000000000",
+				ExpectError:   regexp.MustCompile("of the volume attachment is not valid"),
+			},
+		},
+	})
+}
+
+func TestParseVolumeAttachmentID(t *testing.T) {
+	region, instanceID, volumeID, err := parseVolumeAttachmentID("lon1:12345:67890")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if region != "LON1" || instanceID != "12345" || volumeID != "67890" {
+		t.Fatalf("unexpected parts: %s, %s, %s", region, instanceID, volumeID)
+	}
+
+	for _, id := range []string{"12345:67890", "LON1::67890", "LON1:12345:67890:extra", ""} {
+		if _, _, _, err := parseVolumeAttachmentID(id); err == nil {
+			t.Errorf("expected an error for the ID %q", id)
+		}
+	}
+}
+
+func TestResourceVolumeAttachmentImport_defaults(t *testing.T) {
+	client, closeServer := volumeAttachmentTestClient(t, "12345")
+	defer closeServer()
+
+	d := resourceVolumeAttachment().TestResourceData()
+	d.SetId("LON1:12345:67890")
+
+	imported, err := resourceVolumeAttachmentImport(context.Background(), d, &providerMeta{client: client})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("expected one attachment, got %d", len(imported))
+	}
+
+	// the arguments with a default must be in the state, or the first plan
+	// after the import shows a change for them
+	attributes := imported[0].State().Attributes
+	for key, expected := range map[string]string{"instance_id": "12345", "volume_id": "67890", "region": "LON1", "force_detach": "false"} {
+		if got, ok := attributes[key]; !ok || got != expected {
+			t.Errorf("expected %s to be %s after the import, got %q", key, expected, got)
+		}
+	}
+}
+
+func testAccVolumeAttachmentImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		return fmt.Sprintf("%s:%s:%s", rs.Primary.Attributes["region"], rs.Primary.Attributes["instance_id"], rs.Primary.Attributes["volume_id"]), nil
+	}
+}
@@ -1,18 +1,67 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	_ "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// providerMeta is the meta of the provider, it keeps the client and the options
+// of the provider configuration
+type providerMeta struct {
+	// lock protect the client, it is replaced when the token file has a new token
+	lock   sync.RWMutex
+	client *civogo.Client
+
+	// tokenFile is the token_file of the provider, empty without it
+	tokenFile string
+
+	// protectSSHAccess is protect_ssh_access, the firewall rules check it
+	// before deleting the last rule that allow SSH
+	protectSSHAccess bool
+
+	// requireExplicitNetwork is require_explicit_network, the firewalls don't
+	// fall back to the default network with it
+	requireExplicitNetwork bool
+
+	// failOnMissing is fail_on_missing, the resources that support it fail the
+	// read instead of removing a missing object from the state
+	failOnMissing bool
+
+	// volumeReadCache is the volume cache of cache_volume_reads, nil without
+	// it, the civo_volume_attachment reads use it
+	volumeReadCache *utils.VolumeCache
+
+	// ruleTemplates are the rule_template of the provider by name, the
+	// civo_firewall_rule_template data source expands them
+	ruleTemplates map[string]*firewallRuleTemplate
+}
+
+// Client returns the client of the provider, with the last token read from the
+// token file
+func (p *providerMeta) Client() *civogo.Client {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.client
+}
+
+// volumeReadCacheTTL is how long the volumes listed by a read are reused
+const volumeReadCacheTTL = 30 * time.Second
+
 // Provider Civo cloud provider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"token": {
 				Type:        schema.TypeString,
@@ -20,41 +69,82 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("CIVO_TOKEN", ""),
 				Description: "This is the Civo API token. Alternatively, this can also be specified using `CIVO_TOKEN` environment variable.",
 			},
+			"token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_TOKEN_FILE", ""),
+				Description: "Path to a file with the Civo API token, the file is read again before every operation so the token can be rotated without changing the configuration. If set, it takes precedence over `token`. Alternatively, this can also be specified using `CIVO_TOKEN_FILE` environment variable.",
+			},
 			"region": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CIVO_REGION", ""),
 				Description: "If region is not set, then no region will be used and them you need expensify in every resource even if you expensify here you can overwrite in a resource.",
 			},
+			"fail_on_missing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, reading a `civo_firewall`, `civo_firewall_rule` or `civo_volume_attachment` that doesn't exist anymore fails, instead of removing it from the state and creating it again in the next apply.",
+			},
+			"protect_ssh_access": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, a `civo_firewall_rule` can't be deleted when it is the only ingress rule of the firewall that allows SSH (tcp port 22), to avoid locking out the instances.",
+			},
+			"require_explicit_network": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, a `civo_firewall` without `network_id` fails to be created instead of using the default network of the region, to avoid attaching it to an unintended network in accounts with many networks.",
+			},
+			"cache_volume_reads": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, the reads of `civo_volume_attachment` reuse the volumes listed by the API for 30 seconds, so the refresh of many attachments lists the volumes of a region once instead of once per attachment. A volume changed outside of terraform during the refresh can be read with its previous state.",
+			},
+			"rule_template": ruleTemplateSchema(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			// "civo_template":           dataSourceTemplate(),
-			"civo_disk_image":         dataSourceDiskImage(),
-			"civo_kubernetes_version": dataSourceKubernetesVersion(),
-			"civo_kubernetes_cluster": dataSourceKubernetesCluster(),
-			"civo_instances_size":     dataSourceInstancesSize(),
-			"civo_size":               dataSourceSize(),
-			"civo_instances":          dataSourceInstances(),
-			"civo_instance":           dataSourceInstance(),
-			"civo_dns_domain_name":    dataSourceDNSDomainName(),
-			"civo_dns_domain_record":  dataSourceDNSDomainRecord(),
-			"civo_network":            dataSourceNetwork(),
-			"civo_volume":             dataSourceVolume(),
-			"civo_firewall":           dataSourceFirewall(),
-			"civo_loadbalancer":       dataSourceLoadBalancer(),
-			"civo_ssh_key":            dataSourceSSHKey(),
+			"civo_disk_image":               dataSourceDiskImage(),
+			"civo_kubernetes_version":       dataSourceKubernetesVersion(),
+			"civo_kubernetes_cluster":       dataSourceKubernetesCluster(),
+			"civo_kubernetes_cluster_ready": dataSourceKubernetesClusterReady(),
+			"civo_instances_size":           dataSourceInstancesSize(),
+			"civo_size":                     dataSourceSize(),
+			"civo_instances":                dataSourceInstances(),
+			"civo_instance":                 dataSourceInstance(),
+			"civo_instance_maybe":           dataSourceInstanceMaybe(),
+			"civo_dns_domain":               dataSourceDNSDomain(),
+			"civo_dns_domain_name":          dataSourceDNSDomainName(),
+			"civo_dns_domain_record":        dataSourceDNSDomainRecord(),
+			"civo_network":                  dataSourceNetwork(),
+			"civo_default_network":          dataSourceDefaultNetwork(),
+			"civo_volume":                   dataSourceVolume(),
+			"civo_firewall":                 dataSourceFirewall(),
+			"civo_firewall_drift":           dataSourceFirewallDrift(),
+			"civo_firewall_rule_template":   dataSourceFirewallRuleTemplate(),
+			"civo_reserved_ip":              dataSourceReservedIP(),
+			"civo_loadbalancer":             dataSourceLoadBalancer(),
+			"civo_ssh_key":                  dataSourceSSHKey(),
+			"civo_whoami":                   dataSourceWhoami(),
+			"civo_provider_info":            dataSourceProviderInfo(),
 			// "civo_snapshot":           dataSourceSnapshot(),
 			"civo_region": dataSourceRegion(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"civo_instance":          resourceInstance(),
-			"civo_network":           resourceNetwork(),
-			"civo_volume":            resourceVolume(),
-			"civo_volume_attachment": resourceVolumeAttachment(),
-			"civo_dns_domain_name":   resourceDNSDomainName(),
-			"civo_dns_domain_record": resourceDNSDomainRecord(),
-			"civo_firewall":          resourceFirewall(),
-			"civo_firewall_rule":     resourceFirewallRule(),
+			"civo_instance":           resourceInstance(),
+			"civo_network":            resourceNetwork(),
+			"civo_volume":             resourceVolume(),
+			"civo_volume_attachment":  resourceVolumeAttachment(),
+			"civo_volume_attachments": resourceVolumeAttachments(),
+			"civo_dns_domain_name":    resourceDNSDomainName(),
+			"civo_dns_domain_record":  resourceDNSDomainRecord(),
+			"civo_firewall":           resourceFirewall(),
+			"civo_firewall_rule":      resourceFirewallRule(),
 			// "civo_loadbalancer":         resourceLoadBalancer(),
 			"civo_ssh_key": resourceSSHKey(),
 			// "civo_template": resourceTemplate(),
@@ -62,42 +152,184 @@ func Provider() *schema.Provider {
 			"civo_kubernetes_cluster":   resourceKubernetesCluster(),
 			"civo_kubernetes_node_pool": resourceKubernetesClusterNodePool(),
 		},
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
+
+	for _, resource := range provider.ResourcesMap {
+		withTokenRefresh(resource)
+	}
+	for _, dataSource := range provider.DataSourcesMap {
+		withTokenRefresh(dataSource)
+	}
+
+	return provider
 }
 
 // Provider configuration
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	var regionValue, tokenValue string
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var regionValue, tokenValue, tokenFileValue string
 
 	if region, ok := d.GetOk("region"); ok {
 		regionValue = region.(string)
 	}
 
-	if token, ok := d.GetOk("token"); ok {
+	if tokenFile, ok := d.GetOk("token_file"); ok {
+		tokenFileValue = tokenFile.(string)
+		token, err := readTokenFile(tokenFileValue)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		tokenValue = token
+	} else if token, ok := d.GetOk("token"); ok {
 		tokenValue = token.(string)
 	} else {
-		return nil, fmt.Errorf("[ERR] token not found")
+		return nil, diag.Errorf("[ERR] token not found")
+	}
+
+	templates, err := expandRuleTemplates(d.Get("rule_template").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
 	}
 
 	var client *civogo.Client
-	var err error
 
 	apiURL, envExists := os.LookupEnv("CIVO_API_URL")
 	if envExists && apiURL != "" {
 		client, err = civogo.NewClientWithURL(tokenValue, apiURL, regionValue)
 		if err != nil {
-			return nil, err
+			return nil, diag.FromErr(err)
 		}
 		log.Printf("[DEBUG] Civo API URL: %s\n", apiURL)
-		return client, nil
+	} else {
+		client, err = civogo.NewClient(tokenValue, regionValue)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		log.Printf("[DEBUG] Civo API URL: %s\n", "https://api.civo.com")
+	}
+
+	meta := &providerMeta{
+		client:                 client,
+		tokenFile:              tokenFileValue,
+		protectSSHAccess:       d.Get("protect_ssh_access").(bool),
+		requireExplicitNetwork: d.Get("require_explicit_network").(bool),
+		failOnMissing:          d.Get("fail_on_missing").(bool),
+		ruleTemplates:          templates,
 	}
 
-	client, err = civogo.NewClient(tokenValue, regionValue)
+	if d.Get("cache_volume_reads").(bool) {
+		meta.volumeReadCache = utils.NewVolumeCache(volumeReadCacheTTL)
+	}
+
+	return meta, nil
+}
+
+// removeMissingResource remove from the state an object that doesn't exist anymore,
+// or fail if the provider was configured with fail_on_missing. Call it only when
+// the API said the object is not found, any other error must fail the read
+func removeMissingResource(d *schema.ResourceData, m interface{}, kind string) diag.Diagnostics {
+	if m.(*providerMeta).failOnMissing {
+		return diag.Errorf("[ERR] the %s %s was not found, it was probably deleted outside of terraform. "+
+			"fail_on_missing is enabled in the provider, remove it from the state with `terraform state rm` to create it again", kind, d.Id())
+	}
+
+	log.Printf("[WARN] the %s %s was not found, removing it from the state", kind, d.Id())
+	d.SetId("")
+	return nil
+}
+
+// logPlanWarning log a warning found while planning a change. The SDK can't
+// return warnings from the diff, so they are only shown with TF_LOG=WARN
+func logPlanWarning(format string, v ...interface{}) {
+	log.Printf("[WARN] "+format, v...)
+}
+
+// readTokenFile read the token from the file, ignoring the spaces and new lines around it
+func readTokenFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("[ERR] failed to read the token file %s: %s", path, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("[ERR] the token file %s is empty", path)
 	}
-	log.Printf("[DEBUG] Civo API URL: %s\n", "https://api.civo.com")
-	return client, nil
 
+	return token, nil
+}
+
+// refreshToken read again the token file, if the provider was configured with
+// one. The client is never changed, a new token gets a copy of the client, so
+// the operations in progress keep their token
+func (p *providerMeta) refreshToken() error {
+	if p.tokenFile == "" {
+		return nil
+	}
+
+	token, err := readTokenFile(p.tokenFile)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.client.APIKey != token {
+		log.Printf("[INFO] the token in %s changed, using the new token", p.tokenFile)
+		client := *p.client
+		client.APIKey = token
+		p.client = &client
+	}
+
+	return nil
+}
+
+// withTokenRefresh wrap the functions of the resource so the token is read
+// again from the token file before every operation
+func withTokenRefresh(r *schema.Resource) {
+	wrap := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			if err := m.(*providerMeta).refreshToken(); err != nil {
+				return diag.FromErr(err)
+			}
+			return f(ctx, d, m)
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			if err := m.(*providerMeta).refreshToken(); err != nil {
+				return err
+			}
+			return customizeDiff(ctx, d, m)
+		}
+	}
+
+	if r.Importer != nil {
+		if state := r.Importer.State; state != nil {
+			r.Importer.State = func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if err := m.(*providerMeta).refreshToken(); err != nil {
+					return nil, err
+				}
+				return state(d, m)
+			}
+		}
+		if stateContext := r.Importer.StateContext; stateContext != nil {
+			r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if err := m.(*providerMeta).refreshToken(); err != nil {
+					return nil, err
+				}
+				return stateContext(ctx, d, m)
+			}
+		}
+	}
 }
@@ -1,9 +1,18 @@
 package civo
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -27,4 +36,168 @@ func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("CIVO_TOKEN"); v == "" {
 		t.Fatal("CIVO_TOKEN must be set for acceptance tests")
 	}
+
+	// the provider and the resources in the configs must use the same region
+	if v := os.Getenv("CIVO_REGION"); v == "" {
+		os.Setenv("CIVO_REGION", testAccRegion())
+	}
+}
+
+// testAccRegion returns the region of the acceptance tests, from CIVO_REGION or
+// LON1 if it is not set, so the suite can run against any region of Civo
+func testAccRegion() string {
+	if v := os.Getenv("CIVO_REGION"); v != "" {
+		return utils.NormalizeRegion(v)
+	}
+	return "LON1"
+}
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("  my-token\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	token, err := readTokenFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if token != "my-token" {
+		t.Fatalf("expected token my-token, got %s", token)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := readTokenFile(empty); err == nil {
+		t.Fatal("expected an error for an empty token file")
+	}
+
+	if _, err := readTokenFile(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected an error for a missing token file")
+	}
+}
+
+// TestRefreshToken_concurrent rotate the token while many operations refresh
+// it and use the clients, run it with go test -race to check the clients in
+// use are never changed
+func TestRefreshToken_concurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	writeToken := func(token string) {
+		// the file is replaced, so a read never sees it half written
+		tmp := filepath.Join(dir, "token.tmp")
+		if err := ioutil.WriteFile(tmp, []byte(token), 0600); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	writeToken("token-1")
+
+	client, err := civogo.NewClient("token-1", "LON1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	meta := &providerMeta{client: client, tokenFile: path}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := meta.refreshToken(); err != nil {
+					t.Errorf("err: %s", err)
+					return
+				}
+				scoped := utils.RegionScopedClient(meta.Client(), "NYC1")
+				if scoped.APIKey != "token-1" && scoped.APIKey != "token-2" {
+					t.Errorf("unexpected token %s", scoped.APIKey)
+					return
+				}
+			}
+		}()
+	}
+	writeToken("token-2")
+	wg.Wait()
+
+	if err := meta.refreshToken(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if refreshed := meta.Client(); refreshed.APIKey != "token-2" {
+		t.Fatalf("expected the client with the new token, got %s", refreshed.APIKey)
+	}
+	if client.APIKey != "token-1" {
+		t.Fatalf("expected the client in use not to be changed, got %s", client.APIKey)
+	}
+}
+
+func TestRemoveMissingResource(t *testing.T) {
+	meta := &providerMeta{client: &civogo.Client{}}
+
+	d := resourceFirewall().TestResourceData()
+	d.SetId("12345")
+	if diags := removeMissingResource(d, meta, "firewall"); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected the firewall to be removed from the state, got ID %s", d.Id())
+	}
+
+	meta.failOnMissing = true
+
+	d.SetId("12345")
+	if diags := removeMissingResource(d, meta, "firewall"); !diags.HasError() {
+		t.Fatal("expected an error with fail_on_missing")
+	}
+	if d.Id() != "12345" {
+		t.Fatalf("expected the firewall to be kept in the state, got ID %s", d.Id())
+	}
+}
+
+func TestResourceFirewallRead_apiError(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if status != http.StatusOK {
+			rw.WriteHeader(status)
+			fmt.Fprint(rw, `{"code": "internal_error", "reason": "failed"}`)
+			return
+		}
+		fmt.Fprint(rw, `[]`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// an error of the API is not a deleted firewall, it must stay in the state
+	for _, code := range []int{http.StatusInternalServerError, http.StatusUnauthorized} {
+		status = code
+
+		d := resourceFirewall().TestResourceData()
+		d.SetId("12345")
+		if diags := resourceFirewallRead(context.Background(), d, &providerMeta{client: client}); !diags.HasError() {
+			t.Fatalf("expected an error for the HTTP status %d", code)
+		}
+		if d.Id() != "12345" {
+			t.Fatalf("expected the firewall to be kept in the state after the HTTP status %d", code)
+		}
+	}
+
+	status = http.StatusOK
+	d := resourceFirewall().TestResourceData()
+	d.SetId("12345")
+	if diags := resourceFirewallRead(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected the missing firewall to be removed from the state, got ID %s", d.Id())
+	}
 }
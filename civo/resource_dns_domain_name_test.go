@@ -93,7 +93,7 @@ func testAccCheckCivoDNSDomainNameResourceExists(n string, domain *civogo.DNSDom
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindDNSDomain(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Domain not found: (%s) %s", rs.Primary.ID, err)
@@ -117,7 +117,7 @@ func testAccCheckCivoDNSDomainNameUpdated(domain *civogo.DNSDomain, name string)
 }
 
 func testAccCheckCivoDNSDomainNameDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_dns_domain_name" {
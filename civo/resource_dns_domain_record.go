@@ -87,7 +87,7 @@ func resourceDNSDomainRecord() *schema.Resource {
 
 // function to create a new record for the main domain
 func resourceDNSDomainRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] configuring the domain record %s", d.Get("name").(string))
 	config := &civogo.DNSRecordConfig{
@@ -126,7 +126,7 @@ func resourceDNSDomainRecordCreate(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[INFO] Creating the domain record %s", d.Get("name").(string))
 	dnsDomainRecord, err := apiClient.CreateDNSRecord(d.Get("domain_id").(string), config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new domain record: %s", err)
+		return utils.DiagError("[ERR] failed to create a new domain record", err)
 	}
 
 	d.SetId(dnsDomainRecord.ID)
@@ -136,7 +136,7 @@ func resourceDNSDomainRecordCreate(ctx context.Context, d *schema.ResourceData,
 
 // function to read a dns domain record
 func resourceDNSDomainRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] retriving the domain record %s", d.Get("name").(string))
 	resp, err := apiClient.GetDNSRecord(d.Get("domain_id").(string), d.Id())
@@ -146,7 +146,7 @@ func resourceDNSDomainRecordRead(ctx context.Context, d *schema.ResourceData, m
 			return nil
 		}
 
-		return diag.Errorf("[WARN] error retrieving domain record: %s", err)
+		return utils.DiagError("[WARN] error retrieving domain record", err)
 	}
 
 	d.Set("name", resp.Name)
@@ -165,7 +165,7 @@ func resourceDNSDomainRecordRead(ctx context.Context, d *schema.ResourceData, m
 
 // function to update a dns domain record
 func resourceDNSDomainRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	resp, err := apiClient.GetDNSRecord(d.Get("domain_id").(string), d.Id())
 	if err != nil {
@@ -212,7 +212,7 @@ func resourceDNSDomainRecordUpdate(ctx context.Context, d *schema.ResourceData,
 
 //function to delete a dns domain record
 func resourceDNSDomainRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] Searching the domain record %s", d.Get("name").(string))
 	resp, err := apiClient.GetDNSRecord(d.Get("domain_id").(string), d.Id())
@@ -231,7 +231,7 @@ func resourceDNSDomainRecordDelete(ctx context.Context, d *schema.ResourceData,
 
 // custom import to able to add a main domain to the terraform
 func resourceDNSDomainRecordImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	domainID, DomainRecordID, err := utils.ResourceCommonParseID(d.Id())
 	if err != nil {
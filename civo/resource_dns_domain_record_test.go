@@ -94,7 +94,7 @@ func testAccCheckCivoDNSDomainNameRecordResourceExists(n string, domainRecord *c
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.GetDNSRecord(rs.Primary.Attributes["domain_id"], rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Domain record not found: (%s) %s", rs.Primary.ID, err)
@@ -118,7 +118,7 @@ func testAccCheckCivoDNSDomainNameRecordUpdated(domainRecord *civogo.DNSRecord,
 }
 
 func testAccCheckCivoDNSDomainNameRecordDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_dns_domain_record" {
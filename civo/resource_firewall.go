@@ -2,18 +2,21 @@ package civo
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Firewall resource with this we can create and manage all firewall
 func resourceFirewall() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Civo firewall resource. This can be used to create, modify, and delete firewalls.",
+		Description: "Provides a Civo firewall resource. This can be used to create, modify, and delete firewalls. A firewall belongs to a single network, unlike security groups that span a whole VPC, so the same rules in several networks need a firewall per network.",
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -22,9 +25,16 @@ func resourceFirewall() *schema.Resource {
 				Description:  "The firewall name",
 			},
 			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The firewall region, if is not defined we use the global defined in the provider",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
+			},
+			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "The firewall region, if is not defined we use the global defined in the provider",
+				Description: "Free-text notes about the purpose of this firewall, the Civo API doesn't store it so it is only kept in the terraform state and it can be changed without recreating the firewall",
 			},
 			"create_default_rules": {
 				Type:        schema.TypeBool,
@@ -36,11 +46,12 @@ func resourceFirewall() *schema.Resource {
 			// As the backend has no support for updating network ID we replace it if the
 			// network_id changes
 			"network_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "The firewall network, if is not defined we use the default network",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateSingleID,
+				Description:  "The ID of the one network the firewall belongs to, a firewall can't be shared between networks. If is not defined we use the default network, unless `require_explicit_network` is enabled in the provider",
 			},
 		},
 		CreateContext: resourceFirewallCreate,
@@ -48,14 +59,15 @@ func resourceFirewall() *schema.Resource {
 		UpdateContext: resourceFirewallUpdate,
 		DeleteContext: resourceFirewallDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceFirewallImport,
 		},
+		CustomizeDiff: customizeDiffFirewall,
 	}
 }
 
 // function to create a firewall
 func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 	var networkID string
 	var CreateRules bool
 
@@ -66,10 +78,12 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	if attr, ok := d.GetOk("network_id"); ok {
 		networkID = attr.(string)
+	} else if m.(*providerMeta).requireExplicitNetwork {
+		return diag.Errorf("[ERR] the firewall %s doesn't have a network_id, it is required because require_explicit_network is enabled in the provider", d.Get("name").(string))
 	} else {
-		network, err := apiClient.GetDefaultNetwork()
+		network, err := getDefaultNetwork(apiClient)
 		if err != nil {
-			return diag.Errorf("[ERR] failed to get the default network: %s", err)
+			return utils.DiagError("[ERR] failed to get the default network", err)
 		}
 		networkID = network.ID
 	}
@@ -80,7 +94,7 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	firewall, err := apiClient.NewFirewall(d.Get("name").(string), networkID, &CreateRules)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new firewall: %s", err)
+		return utils.DiagError("[ERR] failed to create a new firewall", err)
 	}
 
 	d.SetId(firewall.ID)
@@ -90,7 +104,7 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 // function to read a firewall
 func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -100,23 +114,24 @@ func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Printf("[INFO] retriving the firewall %s", d.Id())
 	resp, err := apiClient.FindFirewall(d.Id())
 	if err != nil {
-		if resp == nil {
-			d.SetId("")
-			return nil
+		if utils.IsNotFoundError(err) {
+			return removeMissingResource(d, m, "firewall")
 		}
 
-		return diag.Errorf("[ERR] error retrieving firewall: %s", err)
+		return utils.DiagError("[ERR] error retrieving firewall", err)
 	}
 
 	d.Set("name", resp.Name)
 	d.Set("network_id", resp.NetworkID)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
 
 	return nil
 }
 
-// function to update the firewall
+// function to update the firewall, the description is not send to the API,
+// so we only need to keep it in the state
 func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -133,6 +148,11 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			if err != nil {
 				return diag.Errorf("[WARN] an error occurred while tring to rename the firewall %s, %s", d.Id(), err)
 			}
+
+			// the API can accept the rename without applying it, so we check the new name
+			if err := waitForFirewallName(ctx, apiClient, d.Id(), firewall.Name, time.Minute, 2*time.Second); err != nil {
+				return diag.Errorf("[ERR] the firewall %s was not renamed to %s: %s", d.Id(), firewall.Name, err)
+			}
 		}
 	}
 
@@ -141,7 +161,7 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 
 // function to delete a firewall
 func resourceFirewallDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -163,3 +183,69 @@ func resourceFirewallDelete(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	return nil
 }
+
+// custom import to able to import a firewall from any region, the firewall is
+// looked for in the region of the provider first and then in the other regions,
+// the rules are imported with the firewall_id:* ID of civo_firewall_rule
+func resourceFirewallImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	region, _ := d.Get("region").(string)
+	apiClient, err := findFirewallRegion(m.(*providerMeta).Client(), region, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO] retriving the firewall %s", d.Id())
+	firewall, err := apiClient.FindFirewall(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("[ERR] the firewall %s was not found in the region %s: %s", d.Id(), apiClient.Region, err)
+	}
+
+	d.SetId(firewall.ID)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
+	// the default of create_default_rules is not set on import, without it
+	// the first plan would replace the firewall
+	d.Set("create_default_rules", true)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// waitForFirewallName wait until the firewall has the name
+func waitForFirewallName(ctx context.Context, apiClient *civogo.Client, firewallID string, name string, timeout time.Duration, pollInterval time.Duration) error {
+	renameStateConf := &resource.StateChangeConf{
+		Pending: []string{"renaming"},
+		Target:  []string{"renamed"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.FindFirewall(firewallID)
+			if err != nil {
+				return 0, "", err
+			}
+			if resp.Name != name {
+				log.Printf("[DEBUG] the firewall %s is still named %s", firewallID, resp.Name)
+				return resp, "renaming", nil
+			}
+			return resp, "renamed", nil
+		},
+		Timeout:      timeout,
+		PollInterval: pollInterval,
+	}
+	_, err := renameStateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// custom diff for the firewall, the users coming from other clouds expect
+// a firewall to span many networks, moving it to another network recreates
+// it, so we log a warning while planning it. It is log-only, the create of
+// the new firewall can't tell it replaces an old one to return it on apply
+func customizeDiffFirewall(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("network_id") {
+		return nil
+	}
+
+	oldNetwork, newNetwork := d.GetChange("network_id")
+	if oldNetwork.(string) == "" {
+		return nil
+	}
+
+	logPlanWarning("the firewall %s belongs only to the network %s, changing the network_id to %s recreates the firewall and its rules are not moved. To use the same rules in both networks, create a firewall per network", d.Get("name").(string), oldNetwork.(string), newNetwork.(string))
+	return nil
+}
@@ -2,7 +2,12 @@ package civo
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
@@ -11,12 +16,52 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// firewallRuleService is the protocol and port of a well-known service
+type firewallRuleService struct {
+	Protocol string
+	Port     string
+}
+
+// firewallRuleServices map the names accepted in the service of a rule
+// to the protocol and port to open
+var firewallRuleServices = map[string]firewallRuleService{
+	"dns":        {Protocol: "udp", Port: "53"},
+	"http":       {Protocol: "tcp", Port: "80"},
+	"https":      {Protocol: "tcp", Port: "443"},
+	"imaps":      {Protocol: "tcp", Port: "993"},
+	"kubernetes": {Protocol: "tcp", Port: "6443"},
+	"mysql":      {Protocol: "tcp", Port: "3306"},
+	"ntp":        {Protocol: "udp", Port: "123"},
+	"postgresql": {Protocol: "tcp", Port: "5432"},
+	"rdp":        {Protocol: "tcp", Port: "3389"},
+	"redis":      {Protocol: "tcp", Port: "6379"},
+	"smtp":       {Protocol: "tcp", Port: "25"},
+	"ssh":        {Protocol: "tcp", Port: "22"},
+	"submission": {Protocol: "tcp", Port: "587"},
+}
+
+// firewallRuleServiceNames returns the sorted names of the supported services
+func firewallRuleServiceNames() []string {
+	names := make([]string, 0, len(firewallRuleServices))
+	for name := range firewallRuleServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firewallRuleMutexKV serialize the create and delete of the rules of the same
+// firewall, the backend can fail if we send many changes in parallel to the same
+// firewall, the changes to different firewalls still run in parallel
+var firewallRuleMutexKV = utils.NewMutexKV()
+
 // Firewall Rule resource represent you can create and manage all firewall rules
 // this resource don't have an update option because the backend don't have the
-// support for that, so in this case we use ForceNew for all object in the resource
+// support for that, so in this case we use ForceNew for all object in the resource,
+// except the description and the rate limit that are only kept in the terraform state
 func resourceFirewallRule() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Civo firewall rule resource. This can be used to create, modify, and delete firewalls rules. This resource don't have an update option because Civo backend doesn't support it at this moment. In that case, we use `ForceNew` for all object in the resource.",
+		Description: "Provides a Civo firewall rule resource. This can be used to create, modify, and delete firewalls rules. This resource don't have an update option because Civo backend doesn't support it at this moment. In that case, we use `ForceNew` for all object in the resource, except `description` and `rate_limit` that can be changed in place. A warning is returned when a new rule is already fully covered by an existing rule of the firewall.",
 		Schema: map[string]*schema.Schema{
 			"firewall_id": {
 				Type:         schema.TypeString,
@@ -26,11 +71,13 @@ func resourceFirewallRule() *schema.Resource {
 				Description:  "The Firewall ID",
 			},
 			"protocol": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "The protocol choice from `tcp`, `udp` or `icmp` (the default if unspecified is `tcp`)",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "tcp",
+				ForceNew:         true,
+				ConflictsWith:    []string{"service"},
+				DiffSuppressFunc: suppressFirewallRuleServiceProtocol,
+				Description:      "The protocol choice from `tcp`, `udp` or `icmp` (the default if unspecified is `tcp`). The IP protocols used by the VPN tunnels, like `esp`, `ah` or `gre`, are not supported by the Civo API",
 				ValidateFunc: validation.StringInSlice([]string{
 					"tcp",
 					"udp",
@@ -38,33 +85,54 @@ func resourceFirewallRule() *schema.Resource {
 				}, false),
 			},
 			"start_port": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"service"},
+				Description:   "The start of the port range to configure for this rule (or the single port if required)",
+				ValidateFunc:  validation.NoZeroValues,
+			},
+			"end_port": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"service"},
+				Description:   "The end of the port range (this is optional, by default it will only apply to the single port listed in start_port)",
+				ValidateFunc:  validation.NoZeroValues,
+			},
+			"service": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(firewallRuleServiceNames(), false),
+				Description: "A well-known service name to set the `protocol` and the port of the rule, instead of `protocol`, `start_port` and `end_port`. " +
+					"The supported services are: `" + strings.Join(firewallRuleServiceNames(), "`, `") + "`",
+			},
+			"cidr": {
+				Type:         schema.TypeSet,
+				Optional:     true,
 				Computed:     true,
 				ForceNew:     true,
-				Description:  "The start of the port range to configure for this rule (or the single port if required)",
-				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"cidr", "cidr_from_instance"},
+				Description:  "The CIDR notation of the other end to affect, or a valid network CIDR (e.g. 0.0.0.0/0 to open for everyone or 1.2.3.4/32 to open just for a specific IP address)",
+				Elem:         &schema.Schema{Type: schema.TypeString},
 			},
-			"end_port": {
+			"cidr_from_instance": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				Computed:     true,
 				ForceNew:     true,
-				Description:  "The end of the port range (this is optional, by default it will only apply to the single port listed in start_port)",
+				ExactlyOneOf: []string{"cidr", "cidr_from_instance"},
 				ValidateFunc: validation.NoZeroValues,
-			},
-			"cidr": {
-				Type:        schema.TypeSet,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The CIDR notation of the other end to affect, or a valid network CIDR (e.g. 0.0.0.0/0 to open for everyone or 1.2.3.4/32 to open just for a specific IP address)",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ID of an instance to use its IP address as the `cidr` of the rule (the public IP, or the private IP if the instance doesn't have one). " +
+					"The IP is checked in every plan and the rule is recreated if it changed",
 			},
 			"direction": {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The direction of the rule can be ingress or egress",
+				Description: "The direction of the rule can be ingress or egress, a warning is returned when an egress deny to `0.0.0.0/0` or `::/0` is created because it blocks all the outbound traffic",
 				ValidateFunc: validation.StringInSlice([]string{
 					"ingress", "egress",
 				}, false),
@@ -86,27 +154,42 @@ func resourceFirewallRule() *schema.Resource {
 				Description:  "A string that will be the displayed name/reference for this rule",
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
-			"region": {
-				Type:         schema.TypeString,
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Free-text notes about this rule, the Civo API doesn't store it so it is only kept in the terraform state and it can be changed without recreating the rule",
+			},
+			"rate_limit": {
+				Type:         schema.TypeInt,
 				Optional:     true,
-				Computed:     true,
-				ForceNew:     true,
-				Description:  "The region for this rule",
-				ValidateFunc: validation.StringIsNotEmpty,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "Reserved for rate-limited rules, the max requests per second allowed by the rule. Only for `allow` rules with the `tcp` or `udp` protocol. " +
+					"The Civo API doesn't support rate limits yet and doesn't store them, the value is only kept in the terraform state and is lost on import. The rule allows all the matching traffic and a warning is returned when the rule is created or the rate limit is changed",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region for this rule",
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 		},
 		CreateContext: resourceFirewallRuleCreate,
 		ReadContext:   resourceFirewallRuleRead,
+		UpdateContext: resourceFirewallRuleUpdate,
 		DeleteContext: resourceFirewallRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceFirewallRuleImport,
+			StateContext: resourceFirewallRuleImport,
 		},
+		CustomizeDiff: customizeDiffFirewallRule,
 	}
 }
 
 // function to create a new firewall rule
 func resourceFirewallRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -119,6 +202,15 @@ func resourceFirewallRuleCreate(ctx context.Context, d *schema.ResourceData, m i
 		cird[i] = tfCird.(string)
 	}
 
+	// the instance can be created in the same apply, so the IP is not always known at plan time
+	if instanceID, ok := d.GetOk("cidr_from_instance"); ok {
+		instanceCidr, err := instanceIPCidr(apiClient, instanceID.(string))
+		if err != nil {
+			return utils.DiagError("[ERR] failed to get the IP of the instance "+instanceID.(string), err)
+		}
+		cird = []string{instanceCidr}
+	}
+
 	log.Printf("[INFO] configuring a new firewall rule for firewall %s", d.Get("firewall_id").(string))
 	config := &civogo.FirewallRuleConfig{
 		FirewallID: d.Get("firewall_id").(string),
@@ -133,26 +225,53 @@ func resourceFirewallRuleCreate(ctx context.Context, d *schema.ResourceData, m i
 		config.EndPort = attr.(string)
 	}
 
+	// the service can be unknown at plan time, so the protocol and ports are not always set
+	if attr, ok := d.GetOk("service"); ok {
+		service := firewallRuleServices[attr.(string)]
+		config.Protocol = service.Protocol
+		config.StartPort = service.Port
+		config.EndPort = service.Port
+	}
+
 	if attr, ok := d.GetOk("label"); ok {
 		config.Label = attr.(string)
 	}
 
+	if err := checkFirewallRuleQuota(apiClient, config.FirewallID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	firewallRuleMutexKV.Lock(config.FirewallID)
+	defer firewallRuleMutexKV.Unlock(config.FirewallID)
+
 	log.Printf("[INFO] Creating a new firewall rule for firewall %s with config: %+v", d.Get("firewall_id").(string), config)
 	firewallRule, err := apiClient.NewFirewallRule(config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new firewall rule: %s", err)
+		return utils.DiagError("[ERR] failed to create a new firewall rule", err)
 	}
 
 	log.Printf("[INFO] Firewall rule created with ID: %s", firewallRule.ID)
 
 	d.SetId(firewallRule.ID)
 
-	return resourceFirewallRuleRead(ctx, d, m)
+	diags := firewallRuleShadowedWarning(apiClient, &civogo.FirewallRule{
+		ID:         firewallRule.ID,
+		FirewallID: config.FirewallID,
+		Protocol:   config.Protocol,
+		StartPort:  config.StartPort,
+		EndPort:    config.EndPort,
+		Cidr:       config.Cidr,
+		Direction:  config.Direction,
+		Action:     config.Action,
+	})
+	diags = append(diags, firewallRuleRateLimitWarning(d)...)
+	diags = append(diags, firewallRuleEgressDenyAllWarning(d)...)
+	return append(resourceFirewallRuleRead(ctx, d, m), diags...)
 }
 
 // function to read a firewall rule
 func resourceFirewallRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -163,12 +282,11 @@ func resourceFirewallRuleRead(ctx context.Context, d *schema.ResourceData, m int
 
 	resp, err := apiClient.FindFirewallRule(d.Get("firewall_id").(string), d.Id())
 	if err != nil {
-		if resp == nil {
-			d.SetId("")
-			return nil
+		if utils.IsNotFoundError(err) {
+			return removeMissingResource(d, m, "firewall rule")
 		}
 
-		return diag.Errorf("[ERR] error retrieving firewall rule: %s", err)
+		return utils.DiagError("[ERR] error retrieving firewall rule", err)
 	}
 
 	log.Printf("[INFO] Rules response: %+v", resp)
@@ -181,62 +299,507 @@ func resourceFirewallRuleRead(ctx context.Context, d *schema.ResourceData, m int
 		d.Set("end_port", resp.EndPort)
 	}
 
-	d.Set("cidr", resp.Cidr)
+	d.Set("cidr", utils.SortedCidr(resp.Cidr))
 	d.Set("direction", resp.Direction)
 	d.Set("action", resp.Action)
 	d.Set("label", resp.Label)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
 
 	return nil
 }
 
+// function to update a firewall rule, only the description and the rate limit
+// can be updated and they are not send to the API, so we only need to keep
+// them in the state
+func resourceFirewallRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("description") {
+		log.Printf("[INFO] updating the description of the firewall rule %s", d.Id())
+	}
+
+	if !d.HasChange("rate_limit") {
+		return resourceFirewallRuleRead(ctx, d, m)
+	}
+
+	log.Printf("[INFO] updating the rate limit of the firewall rule %s", d.Id())
+	return append(resourceFirewallRuleRead(ctx, d, m), firewallRuleRateLimitWarning(d)...)
+}
+
+// firewallRuleRateLimitWarning returns a warning if the rule has a rate limit,
+// the API doesn't support it so the rule allows all the matching traffic
+func firewallRuleRateLimitWarning(d *schema.ResourceData) diag.Diagnostics {
+	rateLimit, ok := d.GetOk("rate_limit")
+	if !ok {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The rate limit of the firewall rule %s is not enforced", d.Id()),
+		Detail: fmt.Sprintf("The Civo API doesn't support rate limits yet, rate_limit = %d is only kept in the terraform state. "+
+			"The rule allows all the matching traffic", rateLimit.(int)),
+	}}
+}
+
 // function to delete a firewall rule
 func resourceFirewallRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
 		apiClient.Region = region.(string)
 	}
 
+	firewallID := d.Get("firewall_id").(string)
+	firewallRuleMutexKV.Lock(firewallID)
+	defer firewallRuleMutexKV.Unlock(firewallID)
+
+	if m.(*providerMeta).protectSSHAccess {
+		if err := checkSSHAccessKept(apiClient, firewallID, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO] retriving the firewall rule %s", d.Id())
-	_, err := apiClient.DeleteFirewallRule(d.Get("firewall_id").(string), d.Id())
+	_, err := apiClient.DeleteFirewallRule(firewallID, d.Id())
 	if err != nil {
 		return diag.Errorf("[ERR] an error occurred while tring to delete firewall rule %s - %v", d.Id(), err)
 	}
 	return nil
 }
 
-// custom import to able to add a firewall rule to the terraform
-func resourceFirewallRuleImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	apiClient := m.(*civogo.Client)
+// checkSSHAccessKept return an error if the rule is the only ingress rule of
+// the firewall that allow SSH, used when the provider has protect_ssh_access
+func checkSSHAccessKept(apiClient *civogo.Client, firewallID string, ruleID string) error {
+	rules, err := apiClient.ListFirewallRules(firewallID)
+	if err != nil {
+		return fmt.Errorf("[ERR] failed to list the rules of the firewall %s to check the SSH access: %s", firewallID, err)
+	}
 
-	// overwrite the region if is define in the datasource
-	if region, ok := d.GetOk("region"); ok {
-		apiClient.Region = region.(string)
+	deletingSSHRule := false
+	otherSSHRules := 0
+	for _, rule := range rules {
+		if !allowsSSH(rule) {
+			continue
+		}
+		if rule.ID == ruleID {
+			deletingSSHRule = true
+		} else {
+			otherSSHRules++
+		}
 	}
 
+	if deletingSSHRule && otherSSHRules == 0 {
+		return fmt.Errorf("[ERR] the firewall rule %s is the only rule of the firewall %s that allows SSH, deleting it can lock you out of the instances. "+
+			"Add another ingress rule that allows tcp port 22 first, or disable protect_ssh_access in the provider", ruleID, firewallID)
+	}
+
+	return nil
+}
+
+// allowsSSH check if the rule is an ingress allow rule for tcp port 22
+func allowsSSH(rule civogo.FirewallRule) bool {
+	if rule.Direction != "ingress" || rule.Action != "allow" || rule.Protocol != "tcp" {
+		return false
+	}
+
+	startPort, err := strconv.Atoi(rule.StartPort)
+	if err != nil {
+		return false
+	}
+
+	endPort := startPort
+	if rule.EndPort != "" {
+		if endPort, err = strconv.Atoi(rule.EndPort); err != nil {
+			return false
+		}
+	}
+
+	return startPort <= 22 && endPort >= 22
+}
+
+// custom import to able to add a firewall rule to the terraform, the
+// firewall is taken only from the import ID, so it doesn't need to be
+// managed by terraform, the ID firewallID:* import all the rules of the firewall.
+// The firewall is looked for in the region of the provider first and then in
+// the other regions, the region where it was found is saved in the state
+func resourceFirewallRuleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	firewallID, firewallRuleID, err := utils.ResourceCommonParseID(d.Id())
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("[INFO] retriving the firewall rule %s", firewallRuleID)
+	region, _ := d.Get("region").(string)
+	apiClient, err := findFirewallRegion(m.(*providerMeta).Client(), region, firewallID)
+	if err != nil {
+		return nil, err
+	}
+
+	// firewallID:* import all the rules of the firewall, the first rule is kept
+	// in d and each of the other rules is returned in its own resource data
+	if firewallRuleID == "*" {
+		log.Printf("[INFO] retriving all the firewall rules from the firewall %s", firewallID)
+		rules, err := apiClient.ListFirewallRules(firewallID)
+		if err != nil {
+			return nil, fmt.Errorf("[ERR] failed to list the rules of the firewall %s: %s", firewallID, err)
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("[ERR] the firewall %s has no rules to import", firewallID)
+		}
+
+		results := make([]*schema.ResourceData, 0, len(rules))
+		for i := range rules {
+			rule := d
+			if i > 0 {
+				rule = resourceFirewallRule().Data(nil)
+			}
+			setFirewallRuleImportState(rule, firewallID, &rules[i], apiClient.Region)
+			results = append(results, rule)
+		}
+
+		return results, nil
+	}
+
+	log.Printf("[INFO] retriving the firewall rule %s from the firewall %s", firewallRuleID, firewallID)
 	resp, err := apiClient.FindFirewallRule(firewallID, firewallRuleID)
 	if err != nil {
-		if resp != nil {
-			return nil, err
+		if utils.IsNotFoundError(err) {
+			return nil, fmt.Errorf("[ERR] the firewall rule %s was not found in the firewall %s: %s", firewallRuleID, firewallID, err)
 		}
+		return nil, fmt.Errorf("[ERR] failed to read the firewall rule %s of the firewall %s: %s", firewallRuleID, firewallID, err)
 	}
 
-	d.SetId(resp.ID)
-	d.Set("firewall_id", resp.FirewallID)
-	d.Set("protocol", resp.Protocol)
-	d.Set("start_port", resp.StartPort)
-	d.Set("end_port", resp.EndPort)
-	d.Set("cidr", resp.Cidr)
-	d.Set("direction", resp.Direction)
-	d.Set("action", resp.Action)
-	d.Set("label", resp.Label)
+	setFirewallRuleImportState(d, firewallID, resp, apiClient.Region)
 
 	return []*schema.ResourceData{d}, nil
 }
+
+// maxFirewallImportRegions is the max number of regions searched for the
+// imported firewall or for the firewall of an imported rule, so an import
+// never does too many calls
+const maxFirewallImportRegions = 10
+
+// findFirewallRegion returns a client for the region of the firewall, the
+// region is tried first and, if the firewall is not there, the other regions
+// of the account. Only a not found error moves the search to the next region
+func findFirewallRegion(client *civogo.Client, region string, firewallID string) (*civogo.Client, error) {
+	apiClient := utils.RegionScopedClient(client, region)
+	// any other error is reported by the import with the same client
+	_, err := apiClient.ListFirewallRules(firewallID)
+	if err == nil || !utils.IsNotFoundError(err) {
+		return apiClient, nil
+	}
+	notFoundErr := err
+
+	regions, err := client.ListRegions()
+	if err != nil {
+		log.Printf("[WARN] failed to list the regions to look for the firewall %s: %s", firewallID, err)
+		return apiClient, nil
+	}
+
+	searched := 0
+	for _, r := range regions {
+		if utils.NormalizeRegion(r.Code) == utils.NormalizeRegion(apiClient.Region) {
+			continue
+		}
+		if searched == maxFirewallImportRegions {
+			log.Printf("[WARN] stopped looking for the firewall %s after %d regions", firewallID, searched)
+			break
+		}
+		searched++
+
+		regionClient := utils.RegionScopedClient(client, r.Code)
+		log.Printf("[INFO] looking for the firewall %s in the region %s", firewallID, r.Code)
+		if _, err := regionClient.ListFirewallRules(firewallID); err != nil {
+			if utils.IsNotFoundError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("[ERR] failed to look for the firewall %s in the region %s: %s", firewallID, r.Code, err)
+		}
+
+		log.Printf("[INFO] the firewall %s was found in the region %s", firewallID, r.Code)
+		return regionClient, nil
+	}
+
+	return nil, fmt.Errorf("[ERR] the firewall %s was not found in the region %s or in the other %d regions searched: %s", firewallID, apiClient.Region, searched, notFoundErr)
+}
+
+// setFirewallRuleImportState fill the state of an imported rule
+func setFirewallRuleImportState(d *schema.ResourceData, firewallID string, rule *civogo.FirewallRule, region string) {
+	d.SetId(rule.ID)
+	// the API doesn't always return the firewall in the rule, so we use the one in the import ID
+	d.Set("firewall_id", firewallID)
+	d.Set("protocol", rule.Protocol)
+	d.Set("start_port", rule.StartPort)
+	d.Set("end_port", rule.EndPort)
+	d.Set("cidr", utils.SortedCidr(rule.Cidr))
+	d.Set("direction", rule.Direction)
+	d.Set("action", rule.Action)
+	d.Set("label", rule.Label)
+	d.Set("region", utils.NormalizeRegion(region))
+}
+
+// custom diff for the firewall rule, it only warns in the plan about rules
+// that are probably a mistake
+func customizeDiffFirewallRule(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := resolveFirewallRuleService(d); err != nil {
+		return err
+	}
+
+	if err := checkFirewallRuleRateLimit(d); err != nil {
+		return err
+	}
+
+	if err := resolveCidrFromInstance(d, m); err != nil {
+		return err
+	}
+
+	warnFirewallRuleRecreated(d)
+
+	return nil
+}
+
+// checkFirewallRuleRateLimit fail the plan if the rate limit is set on a rule
+// that can't have it, only the tcp and udp allow rules can be rate limited
+func checkFirewallRuleRateLimit(d *schema.ResourceDiff) error {
+	if _, ok := d.GetOk("rate_limit"); !ok {
+		return nil
+	}
+
+	if action := d.Get("action").(string); d.NewValueKnown("action") && action != "allow" {
+		return fmt.Errorf("[ERR] rate_limit can only be set on allow rules, the action of the rule is %s", action)
+	}
+
+	if protocol := firewallRuleProtocol(d); d.NewValueKnown("protocol") && protocol != "" && protocol != "tcp" && protocol != "udp" {
+		return fmt.Errorf("[ERR] rate_limit can only be set on tcp and udp rules, the protocol of the rule is %s", protocol)
+	}
+
+	return nil
+}
+
+// checkFirewallRuleQuota fail the create of a rule if the account already
+// uses all the firewall rules of its quota, so the error shows the usage and
+// the limit. It is only checked on apply to keep the plan free of API calls
+func checkFirewallRuleQuota(apiClient *civogo.Client, firewallID string) error {
+	quota, err := apiClient.GetQuota()
+	if err != nil {
+		// the quota is only a preflight, the create reports the real error
+		log.Printf("[DEBUG] unable to get the quota to check the firewall rules: %s", err)
+		return nil
+	}
+
+	return firewallRuleQuotaError(quota, firewallID)
+}
+
+// firewallRuleQuotaError returns an error with the counts if there is no room
+// in the quota for one more rule, a limit of 0 means the API didn't return it
+func firewallRuleQuotaError(quota *civogo.Quota, firewallID string) error {
+	if quota.SecurityGroupRuleLimit > 0 && quota.SecurityGroupRuleUsage >= quota.SecurityGroupRuleLimit {
+		return fmt.Errorf("[ERR] the account already uses %d of the %d firewall rules of its quota, the rule for the firewall %s can't be created. Remove some rules or ask Civo to increase the quota", quota.SecurityGroupRuleUsage, quota.SecurityGroupRuleLimit, firewallID)
+	}
+
+	return nil
+}
+
+// firewallRuleForceNewKeysOnce build the list of firewallRuleForceNewKeys
+// only once, the schema is the same for every diff
+var (
+	firewallRuleForceNewKeysOnce sync.Once
+	firewallRuleForceNewKeysList []string
+)
+
+// firewallRuleForceNewKeys returns the sorted attributes of the rule that
+// recreate it when they change, taken from the schema so they stay in sync
+func firewallRuleForceNewKeys() []string {
+	firewallRuleForceNewKeysOnce.Do(func() {
+		keys := make([]string, 0)
+		for key, value := range resourceFirewallRule().Schema {
+			if value.ForceNew {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		firewallRuleForceNewKeysList = keys
+	})
+	return firewallRuleForceNewKeysList
+}
+
+// warnFirewallRuleRecreated warn when a change recreates an existing rule, the
+// rule is deleted before the new one is created so there is a gap in the
+// traffic it allows or denies. It is log-only, the create of the new rule
+// can't tell it replaces an old one to return it on apply
+func warnFirewallRuleRecreated(d *schema.ResourceDiff) {
+	if d.Id() == "" {
+		return
+	}
+
+	changed := make([]string, 0)
+	for _, key := range firewallRuleForceNewKeys() {
+		if d.HasChange(key) {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	logPlanWarning("%s", firewallRuleRecreateMessage(d.Id(), d.Get("action").(string), changed))
+}
+
+// firewallRuleRecreateMessage explain why and how the rule is recreated
+func firewallRuleRecreateMessage(ruleID string, action string, changed []string) string {
+	gap := "the traffic it allows is blocked"
+	if action == "deny" {
+		gap = "the traffic it denies is allowed"
+	}
+
+	return fmt.Sprintf("the firewall rule %s will be destroyed and created again because %s changed, the Civo API can't update a rule. "+
+		"Between the delete and the create %s. Only description and rate_limit are changed in place, "+
+		"add create_before_destroy to the lifecycle of the rule to create the new rule first", ruleID, strings.Join(changed, ", "), gap)
+}
+
+// firewallRuleEgressDenyAllWarning returns a warning for an egress deny to
+// everyone, it cut all the outbound traffic of the instances, so we ask to
+// confirm the intent
+func firewallRuleEgressDenyAllWarning(d *schema.ResourceData) diag.Diagnostics {
+	if d.Get("direction").(string) != "egress" || d.Get("action").(string) != "deny" {
+		return nil
+	}
+
+	for _, cidr := range d.Get("cidr").(*schema.Set).List() {
+		if cidr.(string) == "0.0.0.0/0" || cidr.(string) == "::/0" {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The firewall rule %s denies all the egress traffic", d.Id()),
+				Detail: fmt.Sprintf("The rule for the firewall %s denies all the egress traffic to %s, the instances using this firewall will not be able to reach the internet. "+
+					"Please confirm this is intended", d.Get("firewall_id").(string), cidr.(string)),
+			}}
+		}
+	}
+
+	return nil
+}
+
+// firewallRuleShadowedWarning returns a warning if the new rule is fully
+// covered by one of the other rules of the firewall, the rule is redundant in
+// that case
+func firewallRuleShadowedWarning(apiClient *civogo.Client, newRule *civogo.FirewallRule) diag.Diagnostics {
+	rules, err := apiClient.ListFirewallRules(newRule.FirewallID)
+	if err != nil {
+		// the check is only informational, the rule is already created
+		log.Printf("[DEBUG] unable to list the rules of the firewall %s to check for redundant rules: %s", newRule.FirewallID, err)
+		return nil
+	}
+
+	for _, rule := range rules {
+		if rule.ID == newRule.ID {
+			continue
+		}
+		if utils.FirewallRuleShadowedBy(*newRule, rule) {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The firewall rule %s is redundant", newRule.ID),
+				Detail:   fmt.Sprintf("The rule %v for the firewall %s is already covered by the rule %s (%s)", newRule.Cidr, newRule.FirewallID, rule.ID, rule.Label),
+			}}
+		}
+	}
+
+	return nil
+}
+
+// suppressFirewallRuleServiceProtocol ignore the default protocol of a rule
+// with a service, the protocol of the rule comes from the service
+func suppressFirewallRuleServiceProtocol(k, old, new string, d *schema.ResourceData) bool {
+	return d.Get("service").(string) != ""
+}
+
+// firewallRuleProtocol returns the protocol of the rule in the plan, the one
+// of the service if it is set
+func firewallRuleProtocol(d *schema.ResourceDiff) string {
+	if service, ok := firewallRuleServices[d.Get("service").(string)]; ok {
+		return service.Protocol
+	}
+	return d.Get("protocol").(string)
+}
+
+// resolveFirewallRuleService set the ports of the rule from the service, the
+// protocol has the tcp default so it is only resolved with firewallRuleProtocol
+func resolveFirewallRuleService(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("service") {
+		return nil
+	}
+
+	name, ok := d.GetOk("service")
+	if !ok {
+		return nil
+	}
+
+	service, ok := firewallRuleServices[name.(string)]
+	if !ok {
+		return fmt.Errorf("[ERR] unknown service %s for the firewall rule, the supported services are: %s", name.(string), strings.Join(firewallRuleServiceNames(), ", "))
+	}
+
+	for key, value := range map[string]string{"start_port": service.Port, "end_port": service.Port} {
+		if d.Get(key).(string) == value {
+			continue
+		}
+		if err := d.SetNew(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveCidrFromInstance set the cidr of the rule to the current IP of the
+// instance in cidr_from_instance, if the IP changed the rule is recreated
+func resolveCidrFromInstance(d *schema.ResourceDiff, m interface{}) error {
+	instanceID, ok := d.GetOk("cidr_from_instance")
+	if !ok || !d.NewValueKnown("cidr_from_instance") {
+		return nil
+	}
+
+	// use a client for the region of the rule, the diff can't change the shared client
+	region, _ := d.Get("region").(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+
+	instanceCidr, err := instanceIPCidr(apiClient, instanceID.(string))
+	if err != nil {
+		// the instance can be replaced in the same apply, the create will resolve the IP
+		log.Printf("[DEBUG] unable to get the IP of the instance %s for the firewall rule: %s", instanceID.(string), err)
+		return nil
+	}
+
+	current := d.Get("cidr").(*schema.Set)
+	if current.Len() == 1 && current.Contains(instanceCidr) {
+		return nil
+	}
+
+	log.Printf("[INFO] the IP of the instance %s is %s, the cidr of the firewall rule will be updated", instanceID.(string), instanceCidr)
+	if err := d.SetNew("cidr", []string{instanceCidr}); err != nil {
+		return err
+	}
+	if d.Id() != "" {
+		return d.ForceNew("cidr")
+	}
+
+	return nil
+}
+
+// instanceIPCidr returns the IP of the instance as a /32 cidr, the public IP
+// or the private IP if the instance doesn't have a public one
+func instanceIPCidr(apiClient *civogo.Client, instanceID string) (string, error) {
+	instance, err := apiClient.GetInstance(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	ip := instance.PublicIP
+	if ip == "" {
+		ip = instance.PrivateIP
+	}
+	if ip == "" {
+		return "", fmt.Errorf("the instance %s doesn't have an IP address yet", instanceID)
+	}
+
+	return ip + "/32", nil
+}
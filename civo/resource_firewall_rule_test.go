@@ -1,10 +1,16 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -79,6 +85,133 @@ func TestAccCivoFirewallRule_update(t *testing.T) {
 	})
 }
 
+func TestAccCivoFirewallRule_description(t *testing.T) {
+	var firewallRule civogo.FirewallRule
+	var updatedFirewallRule civogo.FirewallRule
+
+	// generate a random name for each test run
+	resName := "civo_firewall_rule.testrule"
+	var firewalName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigDescription(firewalName, "the web server"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &firewallRule),
+					resource.TestCheckResourceAttr(resName, "label", "web"),
+					resource.TestCheckResourceAttr(resName, "description", "the web server"),
+				),
+			},
+			{
+				Config: testAccCheckCivoFirewallRuleConfigDescription(firewalName, "the public web server"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &updatedFirewallRule),
+					testAccCheckCivoFirewallRuleNotRecreated(&firewallRule, &updatedFirewallRule),
+					resource.TestCheckResourceAttr(resName, "label", "web"),
+					resource.TestCheckResourceAttr(resName, "description", "the public web server"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCivoFirewallRule_defaultProtocol(t *testing.T) {
+	var firewallRule civogo.FirewallRule
+
+	// generate a random name for each test run
+	resName := "civo_firewall_rule.testrule"
+	var firewalName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigNoProtocol(firewalName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &firewallRule),
+					testAccCheckCivoFirewallRuleValues(&firewallRule),
+					resource.TestCheckResourceAttr(resName, "protocol", "tcp"),
+				),
+			},
+			{
+				// a second plan with the same configuration must be empty
+				Config:   testAccCheckCivoFirewallRuleConfigNoProtocol(firewalName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccCivoFirewallRule_ICMP(t *testing.T) {
+	var firewallRule civogo.FirewallRule
+
+	// generate a random name for each test run
+	resName := "civo_firewall_rule.testrule"
+	var firewalName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigICMP(firewalName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &firewallRule),
+					testAccCheckCivoFirewallRuleProtocolAndCidr(&firewallRule, "icmp", "0.0.0.0/0"),
+					resource.TestCheckResourceAttr(resName, "protocol", "icmp"),
+					resource.TestCheckResourceAttr(resName, "cidr.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resName, "cidr.*", "0.0.0.0/0"),
+					resource.TestCheckResourceAttr(resName, "label", "ping"),
+				),
+			},
+			{
+				// a second plan with the same configuration must be empty
+				Config:   testAccCheckCivoFirewallRuleConfigICMP(firewalName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccCivoFirewallRule_IPv6(t *testing.T) {
+	var firewallRule civogo.FirewallRule
+
+	// generate a random name for each test run
+	resName := "civo_firewall_rule.testrule"
+	var firewalName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigIPv6(firewalName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &firewallRule),
+					testAccCheckCivoFirewallRuleProtocolAndCidr(&firewallRule, "tcp", "2001:db8::/32"),
+					resource.TestCheckResourceAttr(resName, "protocol", "tcp"),
+					resource.TestCheckResourceAttr(resName, "start_port", "443"),
+					resource.TestCheckResourceAttr(resName, "cidr.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resName, "cidr.*", "2001:db8::/32"),
+				),
+			},
+			{
+				// a second plan with the same configuration must be empty
+				Config:   testAccCheckCivoFirewallRuleConfigIPv6(firewalName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func testAccCheckCivoFirewallRuleValues(firewall *civogo.FirewallRule) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if firewall.Protocol != "tcp" {
@@ -101,7 +234,7 @@ func testAccCheckCivoFirewallRuleResourceExists(n string, firewall *civogo.Firew
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindFirewallRule(rs.Primary.Attributes["firewall_id"], rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Firewall rule not found: (%s) %s", rs.Primary.ID, err)
@@ -127,8 +260,31 @@ func testAccCheckCivoFirewallRuleUpdated(firewall *civogo.FirewallRule) resource
 	}
 }
 
+func testAccCheckCivoFirewallRuleNotRecreated(before, after *civogo.FirewallRule) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.ID != after.ID {
+			return fmt.Errorf("the firewall rule was recreated, expected ID \"%s\", got: %#v", before.ID, after.ID)
+		}
+		return nil
+	}
+}
+
+func testAccCheckCivoFirewallRuleProtocolAndCidr(firewall *civogo.FirewallRule, protocol string, cidr string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if firewall.Protocol != protocol {
+			return fmt.Errorf("bad protocol, expected \"%s\", got: %#v", protocol, firewall.Protocol)
+		}
+		for _, c := range firewall.Cidr {
+			if c == cidr {
+				return nil
+			}
+		}
+		return fmt.Errorf("bad cidr, expected \"%s\", got: %#v", cidr, firewall.Cidr)
+	}
+}
+
 func testAccCheckCivoFirewallRuleDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_firewall_rule" {
@@ -182,3 +338,484 @@ resource "civo_firewall_rule" "testrule" {
 }
 `, name)
 }
+
+func testAccCheckCivoFirewallRuleConfigDescription(name string, description string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "tcp"
+	start_port = "80"
+	end_port = "80"
+	cidr = ["192.168.1.2/32"]
+	direction = "ingress"
+	action = "allow"
+	label = "web"
+	description = "%s"
+}
+`, name, description)
+}
+
+func testAccCheckCivoFirewallRuleConfigICMP(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "icmp"
+	cidr = ["0.0.0.0/0"]
+	direction = "ingress"
+	action = "allow"
+	label = "ping"
+}
+`, name)
+}
+
+func testAccCheckCivoFirewallRuleConfigIPv6(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "tcp"
+	start_port = "443"
+	end_port = "443"
+	cidr = ["2001:db8::/32"]
+	direction = "ingress"
+	action = "allow"
+	label = "https-ipv6"
+}
+`, name)
+}
+
+func testAccCheckCivoFirewallRuleConfigNoProtocol(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	start_port = "80"
+	end_port = "80"
+	cidr = ["192.168.1.2/32"]
+	direction = "ingress"
+	action = "allow"
+	label = "web"
+}
+`, name)
+}
+
+func TestAccCivoFirewallRule_rateLimit(t *testing.T) {
+	resName := "civo_firewall_rule.testrule"
+	var firewallName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckCivoFirewallRuleConfigRateLimit(firewallName, "icmp", 10),
+				ExpectError: regexp.MustCompile("rate_limit can only be set on tcp and udp rules"),
+			},
+			{
+				Config: testAccCheckCivoFirewallRuleConfigRateLimit(firewallName, "tcp", 10),
+				Check:  resource.TestCheckResourceAttr(resName, "rate_limit", "10"),
+			},
+			{
+				// the rate limit is only in the state, so it is changed in place
+				Config: testAccCheckCivoFirewallRuleConfigRateLimit(firewallName, "tcp", 20),
+				Check:  resource.TestCheckResourceAttr(resName, "rate_limit", "20"),
+			},
+		},
+	})
+}
+
+func testAccCheckCivoFirewallRuleConfigRateLimit(name string, protocol string, rateLimit int) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	protocol = "%s"
+	start_port = "443"
+	cidr = ["0.0.0.0/0"]
+	direction = "ingress"
+	action = "allow"
+	rate_limit = %d
+}
+`, name, protocol, rateLimit)
+}
+
+func TestFirewallRuleRateLimitWarning(t *testing.T) {
+	d := resourceFirewallRule().TestResourceData()
+	d.SetId("12345")
+
+	if diags := firewallRuleRateLimitWarning(d); len(diags) != 0 {
+		t.Fatalf("expected no warning without rate_limit, got %v", diags)
+	}
+
+	d.Set("rate_limit", 10)
+	diags := firewallRuleRateLimitWarning(d)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning with rate_limit, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "rate_limit = 10 is only kept in the terraform state") {
+		t.Fatalf("unexpected warning: %s", diags[0].Detail)
+	}
+}
+
+func TestAccCivoFirewallRule_cidrFromInstance(t *testing.T) {
+	var firewallName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoFirewallRuleConfigCidrFromInstance(firewallName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("civo_firewall_rule.from_instance", "cidr_from_instance", "civo_instance.vm", "id"),
+					resource.TestCheckResourceAttr("civo_firewall_rule.from_instance", "cidr.#", "1"),
+					// the rule using the IP of the instance in cidr depends on the instance,
+					// a new IP recreates the rule because cidr is ForceNew
+					resource.TestCheckResourceAttr("civo_firewall_rule.from_ip", "cidr.#", "1"),
+				),
+			},
+			{
+				// the IP didn't change, so the rules are not recreated
+				Config:   testAccCheckCivoFirewallRuleConfigCidrFromInstance(firewallName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestInstanceIPCidr(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/instances/12345": `{"id": "12345", "public_ip": "1.2.3.4", "private_ip": "10.0.0.2"}`,
+		"/v2/instances/67890": `{"id": "67890", "private_ip": "10.0.0.3"}`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	cidr, err := instanceIPCidr(client, "12345")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if cidr != "1.2.3.4/32" {
+		t.Fatalf("expected the public IP 1.2.3.4/32, got %s", cidr)
+	}
+
+	cidr, err = instanceIPCidr(client, "67890")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if cidr != "10.0.0.3/32" {
+		t.Fatalf("expected the private IP 10.0.0.3/32, got %s", cidr)
+	}
+}
+
+func testAccCheckCivoFirewallRuleConfigCidrFromInstance(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_instance" "vm" {
+	hostname = "instance-%s"
+}
+
+resource "civo_firewall_rule" "from_instance" {
+	firewall_id = civo_firewall.foobar.id
+	start_port = "22"
+	cidr_from_instance = civo_instance.vm.id
+	direction = "ingress"
+	action = "allow"
+}
+
+resource "civo_firewall_rule" "from_ip" {
+	firewall_id = civo_firewall.foobar.id
+	start_port = "80"
+	cidr = ["${civo_instance.vm.public_ip}/32"]
+	direction = "ingress"
+	action = "allow"
+}
+`, name, name)
+}
+
+func TestAccCivoFirewallRule_service(t *testing.T) {
+	var firewallRule civogo.FirewallRule
+
+	// generate a random name for each test run
+	resName := "civo_firewall_rule.testrule"
+	var firewallName = acctest.RandomWithPrefix("tf-fw-rule")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckCivoFirewallRuleConfigService(firewallName, "gopher"),
+				ExpectError: regexp.MustCompile(`expected service to be one of`),
+			},
+			{
+				Config: testAccCheckCivoFirewallRuleConfigService(firewallName, "dns"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoFirewallRuleResourceExists(resName, &firewallRule),
+					resource.TestCheckResourceAttr(resName, "service", "dns"),
+					resource.TestCheckResourceAttr(resName, "protocol", "udp"),
+					resource.TestCheckResourceAttr(resName, "start_port", "53"),
+					resource.TestCheckResourceAttr(resName, "end_port", "53"),
+				),
+			},
+			{
+				// a second plan with the same configuration must be empty
+				Config:   testAccCheckCivoFirewallRuleConfigService(firewallName, "dns"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCivoFirewallRuleConfigService(name string, service string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+}
+
+resource "civo_firewall_rule" "testrule" {
+	firewall_id = civo_firewall.foobar.id
+	service = "%s"
+	cidr = ["192.168.1.2/32"]
+	direction = "ingress"
+	action = "allow"
+}
+`, name, service)
+}
+
+func TestFirewallRuleServices(t *testing.T) {
+	for name, service := range firewallRuleServices {
+		rule := map[string]interface{}{
+			"protocol":   service.Protocol,
+			"start_port": service.Port,
+			"end_port":   service.Port,
+			"cidr":       []interface{}{},
+		}
+		if err := validateTemplateRule(rule); err != nil {
+			t.Errorf("the service %s is not a valid rule: %s", name, err)
+		}
+	}
+}
+
+func TestResourceFirewallRuleDiff_defaultProtocol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, `[]`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		state    map[string]string
+		config   map[string]interface{}
+		protocol string
+	}{
+		{"protocol removed from the config", map[string]string{"protocol": "udp", "start_port": "80", "end_port": "80"}, map[string]interface{}{"start_port": "80"}, "tcp"},
+		{"protocol in the config", map[string]string{"protocol": "udp", "start_port": "80", "end_port": "80"}, map[string]interface{}{"protocol": "udp", "start_port": "80"}, ""},
+		{"default protocol", map[string]string{"protocol": "tcp", "start_port": "80", "end_port": "80"}, map[string]interface{}{"start_port": "80"}, ""},
+		{"protocol of the service", map[string]string{"protocol": "udp", "service": "dns", "start_port": "53", "end_port": "53"}, map[string]interface{}{"service": "dns"}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "rule",
+				Attributes: map[string]string{
+					"id":          "rule",
+					"firewall_id": "fw",
+					"direction":   "ingress",
+					"action":      "allow",
+					"cidr.#":      "1",
+					"cidr.0":      "0.0.0.0/0",
+					"region":      "LON1",
+				},
+			}
+			for key, value := range c.state {
+				state.Attributes[key] = value
+			}
+			config := map[string]interface{}{
+				"firewall_id": "fw",
+				"direction":   "ingress",
+				"action":      "allow",
+				"cidr":        []interface{}{"0.0.0.0/0"},
+			}
+			for key, value := range c.config {
+				config[key] = value
+			}
+
+			diff, err := resourceFirewallRule().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &providerMeta{client: client})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			protocol := ""
+			if diff != nil && diff.Attributes["protocol"] != nil && diff.Attributes["protocol"].Old != diff.Attributes["protocol"].New {
+				protocol = diff.Attributes["protocol"].New
+			}
+			if protocol != c.protocol {
+				t.Fatalf("expected the protocol to change to %q, got the diff %v", c.protocol, diff)
+			}
+		})
+	}
+}
+
+func TestAllowsSSH(t *testing.T) {
+	cases := []struct {
+		rule     civogo.FirewallRule
+		expected bool
+	}{
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "22"}, true},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "1", EndPort: "65535"}, true},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", EndPort: "443"}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "deny", Protocol: "tcp", StartPort: "22"}, false},
+		{civogo.FirewallRule{Direction: "egress", Action: "allow", Protocol: "tcp", StartPort: "22"}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "udp", StartPort: "22"}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "icmp"}, false},
+	}
+
+	for _, c := range cases {
+		if got := allowsSSH(c.rule); got != c.expected {
+			t.Errorf("allowsSSH(%+v): expected %t, got %t", c.rule, c.expected, got)
+		}
+	}
+}
+
+func TestFirewallRuleQuotaError(t *testing.T) {
+	cases := []struct {
+		usage, limit int
+		fail         bool
+	}{
+		{usage: 10, limit: 100, fail: false},
+		{usage: 99, limit: 100, fail: false},
+		{usage: 100, limit: 100, fail: true},
+		{usage: 120, limit: 100, fail: true},
+		{usage: 120, limit: 0, fail: false},
+	}
+
+	for _, c := range cases {
+		err := firewallRuleQuotaError(&civogo.Quota{SecurityGroupRuleUsage: c.usage, SecurityGroupRuleLimit: c.limit}, "12345")
+		if (err != nil) != c.fail {
+			t.Fatalf("usage %d of %d: expected error %t, got %v", c.usage, c.limit, c.fail, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), fmt.Sprintf("already uses %d of the %d firewall rules", c.usage, c.limit)) {
+			t.Fatalf("unexpected error message: %s", err)
+		}
+	}
+}
+
+func TestFirewallRuleForceNewKeys(t *testing.T) {
+	keys := strings.Join(firewallRuleForceNewKeys(), ",")
+	for _, key := range []string{"cidr", "direction", "firewall_id", "protocol", "start_port"} {
+		if !strings.Contains(keys, key) {
+			t.Fatalf("expected %s to recreate the rule, got %s", key, keys)
+		}
+	}
+	for _, key := range []string{"description", "rate_limit"} {
+		if strings.Contains(keys, key) {
+			t.Fatalf("expected %s to be changed in place, got %s", key, keys)
+		}
+	}
+}
+
+func TestFirewallRuleRecreateMessage(t *testing.T) {
+	message := firewallRuleRecreateMessage("12345", "allow", []string{"cidr", "start_port"})
+	for _, expected := range []string{"the firewall rule 12345 will be destroyed and created again because cidr, start_port changed", "the traffic it allows is blocked", "create_before_destroy"} {
+		if !strings.Contains(message, expected) {
+			t.Fatalf("expected %q in the message, got %s", expected, message)
+		}
+	}
+
+	if message := firewallRuleRecreateMessage("12345", "deny", []string{"cidr"}); !strings.Contains(message, "the traffic it denies is allowed") {
+		t.Fatalf("unexpected message for a deny rule: %s", message)
+	}
+}
+
+func TestFirewallRuleEgressDenyAllWarning(t *testing.T) {
+	cases := []struct {
+		name      string
+		direction string
+		action    string
+		cidr      []interface{}
+		warning   bool
+	}{
+		{"egress deny all", "egress", "deny", []interface{}{"0.0.0.0/0"}, true},
+		{"egress deny all ipv6", "egress", "deny", []interface{}{"10.0.0.0/8", "::/0"}, true},
+		{"egress deny one network", "egress", "deny", []interface{}{"10.0.0.0/8"}, false},
+		{"egress allow all", "egress", "allow", []interface{}{"0.0.0.0/0"}, false},
+		{"ingress deny all", "ingress", "deny", []interface{}{"0.0.0.0/0"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceFirewallRule().TestResourceData()
+			d.SetId("12345")
+			d.Set("direction", c.direction)
+			d.Set("action", c.action)
+			d.Set("cidr", c.cidr)
+
+			diags := firewallRuleEgressDenyAllWarning(d)
+			if c.warning && (len(diags) != 1 || diags[0].Severity != diag.Warning) {
+				t.Fatalf("expected a warning, got %v", diags)
+			}
+			if !c.warning && len(diags) != 0 {
+				t.Fatalf("expected no warning, got %v", diags)
+			}
+		})
+	}
+}
+
+func TestFirewallRuleShadowedWarning(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/firewalls/12345/rules": `[
+			{"id": "1", "firewall_id": "12345", "protocol": "tcp", "start_port": "1", "end_port": "1024", "cidr": ["0.0.0.0/0"], "direction": "ingress", "action": "allow", "label": "low ports"},
+			{"id": "2", "firewall_id": "12345", "protocol": "tcp", "start_port": "22", "end_port": "22", "cidr": ["10.0.0.0/8"], "direction": "ingress", "action": "allow"}
+		]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	rule := &civogo.FirewallRule{ID: "2", FirewallID: "12345", Protocol: "tcp", StartPort: "22", EndPort: "22", Cidr: []string{"10.0.0.0/8"}, Direction: "ingress", Action: "allow"}
+	diags := firewallRuleShadowedWarning(client, rule)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning for the covered rule, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "covered by the rule 1 (low ports)") {
+		t.Fatalf("unexpected warning: %s", diags[0].Detail)
+	}
+
+	rule.StartPort, rule.EndPort = "8080", "8080"
+	if diags := firewallRuleShadowedWarning(client, rule); len(diags) != 0 {
+		t.Fatalf("expected no warning, got %v", diags)
+	}
+}
@@ -1,8 +1,14 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -34,6 +40,7 @@ func TestAccCivoFirewall_basic(t *testing.T) {
 					testAccCheckCivoFirewallValues(&firewall, firewallName),
 					// verify local values
 					resource.TestCheckResourceAttr(resName, "name", firewallName),
+					resource.TestCheckResourceAttr(resName, "region", testAccRegion()),
 				),
 			},
 		},
@@ -68,12 +75,58 @@ func TestAccCivoFirewall_update(t *testing.T) {
 					testAccCheckCivoFirewallResourceExists(resName, &firewall),
 					testAccCheckCivoFirewallUpdated(&firewall, firewallNameUpdate),
 					resource.TestCheckResourceAttr(resName, "name", firewallNameUpdate),
+					resource.TestCheckResourceAttr(resName, "description", "firewall of the web servers"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccCivoFirewall_requireExplicitNetwork(t *testing.T) {
+	var firewallName = acctest.RandomWithPrefix("tf-fw")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckCivoFirewallConfigRequireExplicitNetwork(firewallName),
+				ExpectError: regexp.MustCompile("require_explicit_network is enabled"),
+			},
+		},
+	})
+}
+
+func TestWaitForFirewallName(t *testing.T) {
+	var lists int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// the API returns the old name for the first requests after the rename
+		name := "old-name"
+		if atomic.AddInt32(&lists, 1) > 2 {
+			name = "new-name"
+		}
+		fmt.Fprintf(rw, `[{"id": "12345", "name": "%s"}]`, name)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := waitForFirewallName(context.Background(), client, "12345", "new-name", 5*time.Second, 10*time.Millisecond); err != nil {
+		t.Fatalf("expected the rename to be confirmed, got: %s", err)
+	}
+	if atomic.LoadInt32(&lists) < 3 {
+		t.Fatalf("expected to wait for the rename, the firewall was read %d times", lists)
+	}
+
+	if err := waitForFirewallName(context.Background(), client, "12345", "other-name", 100*time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Fatal("expected an error when the rename is never applied")
+	}
+}
+
 func testAccCheckCivoFirewallValues(firewall *civogo.Firewall, name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if firewall.Name != name {
@@ -93,7 +146,7 @@ func testAccCheckCivoFirewallResourceExists(n string, firewall *civogo.Firewall)
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindFirewall(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Firewall not found: (%s) %s", rs.Primary.ID, err)
@@ -117,7 +170,7 @@ func testAccCheckCivoFirewallUpdated(firewall *civogo.Firewall, name string) res
 }
 
 func testAccCheckCivoFirewallDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_firewall" {
@@ -137,11 +190,25 @@ func testAccCheckCivoFirewallConfigBasic(name string) string {
 	return fmt.Sprintf(`
 resource "civo_firewall" "foobar" {
 	name = "%s"
-}`, name)
+	region = "%s"
+}`, name, testAccRegion())
 }
 
 func testAccCheckCivoFirewallConfigUpdates(name string) string {
 	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+	region = "%s"
+	description = "firewall of the web servers"
+}`, name, testAccRegion())
+}
+
+func testAccCheckCivoFirewallConfigRequireExplicitNetwork(name string) string {
+	return fmt.Sprintf(`
+provider "civo" {
+	require_explicit_network = true
+}
+
 resource "civo_firewall" "foobar" {
 	name = "%s"
 }`, name)
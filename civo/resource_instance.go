@@ -2,6 +2,7 @@ package civo
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -21,9 +22,11 @@ func resourceInstance() *schema.Resource {
 		Description: "Provides a Civo instance resource. This can be used to create, modify, and delete instances.",
 		Schema: map[string]*schema.Schema{
 			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The region for the instance, if not declare we use the region in declared in the provider",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The region for the instance, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 			"hostname": {
 				Type:         schema.TypeString,
@@ -36,14 +39,15 @@ func resourceInstance() *schema.Resource {
 			"reverse_dns": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				Description:  "A fully qualified domain name that should be used as the instance's IP's reverse DNS (optional, uses the hostname if unspecified)",
-				ValidateFunc: utils.ValidateName,
+				Computed:     true,
+				Description:  "A fully qualified domain name that should be used as the instance's IP's reverse DNS (optional, uses the hostname if unspecified). It can be changed without recreating the instance",
+				ValidateFunc: utils.ValidateHostname,
 			},
 			"size": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "g3.xsmall",
-				Description: "The name of the size, from the current list, e.g. g3.xsmall",
+				Description: "The name of the size, from the current list, e.g. g3.xsmall. The instance can be resized to a size with the same or bigger disk without being recreated",
 			},
 			"public_ip_required": {
 				Type:        schema.TypeString,
@@ -72,6 +76,20 @@ func resourceInstance() *schema.Resource {
 				ExactlyOneOf: []string{"template", "disk_image"},
 				Description:  "The ID for the disk image to use to build the instance",
 			},
+			"disk_image_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description: "Pin the exact version of the disk image, the instance fails to be created if the disk image doesn't have this version. " +
+					"If not declared, it is the version the disk image resolved to when the instance was created. A log-only warning, visible with `TF_LOG=WARN`, is logged while planning when the disk image resolves to a different build",
+			},
+			"disk_image_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the disk image the instance was created from",
+			},
 			"initial_user": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -81,12 +99,13 @@ func resourceInstance() *schema.Resource {
 			"notes": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Add some notes to the instance",
+				Description: "Add some notes to the instance, they are shown in the Civo dashboard and can be changed without recreating the instance",
 			},
 			"sshkey_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The ID of an already uploaded SSH public key to use for login to the default user (optional; if one isn't provided a random password will be set and returned in the initial_password field)",
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "The ID of an already uploaded SSH public key to use for login to the default user (optional; if one isn't provided a random password will be set and returned in the initial_password field). " +
+					"The Civo API can't change the key of an existing instance, a new key is only kept in the state and the apply shows a warning",
 			},
 			"firewall_id": {
 				Type:        schema.TypeString,
@@ -95,10 +114,37 @@ func resourceInstance() *schema.Resource {
 				Description: "The ID of the firewall to use, from the current list. If left blank or not sent, the default firewall will be used (open to all)",
 			},
 			"tags": {
-				Type:        schema.TypeSet,
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Description:      "An optional list of tags, represented as a key, value pair. The tags can be changed without recreating the instance, the order of the tags is ignored and their case too unless `tags_ignore_case` is false",
+				Set:              utils.HashTag(false),
+				DiffSuppressFunc: utils.DiffSuppressTagSet("tags_ignore_case"),
+				Elem:             &schema.Schema{Type: schema.TypeString},
+			},
+			"tags_ignore_case": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "An optional list of tags, represented as a key, value pair",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Default:     true,
+				Description: "If enabled, a tag that only changed its case, like `Web` and `web`, is not a change of the tags",
+			},
+			"reserved_ip": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The name, ID or address of a reserved IP to assign to the instance, it can be changed or removed without recreating the instance. Don't manage the assignment of the same reserved IP outside of this attribute, or the assignments will conflict",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"graceful_shutdown": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "If enabled, the instance is stopped and the provider waits for it to shut down before deleting it, so the applications can flush their state. " +
+					"If the instance doesn't stop before the delete timeout, it is deleted anyway. This adds up to the delete timeout (5 minutes by default) to the destroy",
+			},
+			"ignore_ip_changes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, `public_ip` and `private_ip` keep the address read when the instance was created, for users that manage the IPs of the instance outside of terraform",
 			},
 			"script": {
 				Type:     schema.TypeString,
@@ -137,17 +183,17 @@ func resourceInstance() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Sensitive:   true,
-				Description: "Initial password for login",
+				Description: "Initial password for login, it is sensitive so it is hidden in the plan and in the outputs, use `nonsensitive()` to show it",
 			},
 			"private_ip": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Instance's private IP address",
+				Description: "Instance's private IP address, if it is changed by Civo the new value is read so the resources referencing it are updated",
 			},
 			"public_ip": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Instance's public IP address",
+				Description: "Instance's public IP address, if it is changed by Civo the new value is read so the resources referencing it are updated",
 			},
 			"status": {
 				Type:        schema.TypeString,
@@ -169,13 +215,15 @@ func resourceInstance() *schema.Resource {
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
+		CustomizeDiff: customizeDiffInstance,
 	}
 }
 
 // function to create a instance
 func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -185,7 +233,7 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 	log.Printf("[INFO] configuring the instance %s", d.Get("hostname").(string))
 	config, err := apiClient.NewInstanceConfig()
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new config: %s", err)
+		return utils.DiagError("[ERR] failed to create a new config", err)
 	}
 
 	if hostname, ok := d.GetOk("hostname"); ok {
@@ -209,9 +257,9 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 	if networtID, ok := d.GetOk("network_id"); ok {
 		config.NetworkID = networtID.(string)
 	} else {
-		defaultNetwork, err := apiClient.GetDefaultNetwork()
+		defaultNetwork, err := getDefaultNetwork(apiClient)
 		if err != nil {
-			return diag.Errorf("[ERR] failed to get the default network: %s", err)
+			return utils.DiagError("[ERR] failed to get the default network", err)
 		}
 		config.NetworkID = defaultNetwork.ID
 	}
@@ -220,20 +268,21 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 		templateID := ""
 		findTemplate, err := apiClient.FindDiskImage(attr.(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to get the template: %s", err)
+			return utils.DiagError("[ERR] failed to get the template", err)
 		}
 		templateID = findTemplate.ID
 		config.TemplateID = templateID
 	}
 
 	if attr, ok := d.GetOk("disk_image"); ok {
-		diskImageID := ""
-		findDiskImage, err := apiClient.FindDiskImage(attr.(string))
+		findDiskImage, err := findInstanceDiskImage(apiClient, attr.(string), d.Get("disk_image_version").(string))
 		if err != nil {
-			return diag.Errorf("[ERR] failed to get the disk image: %s", err)
+			return utils.DiagError("[ERR] failed to get the disk image", err)
 		}
-		diskImageID = findDiskImage.ID
-		config.TemplateID = diskImageID
+		log.Printf("[INFO] the disk image %s resolved to %s (version %s)", attr.(string), findDiskImage.ID, findDiskImage.Version)
+		config.TemplateID = findDiskImage.ID
+		d.Set("disk_image_id", findDiskImage.ID)
+		d.Set("disk_image_version", findDiskImage.Version)
 	}
 
 	if attr, ok := d.GetOk("initial_user"); ok {
@@ -248,37 +297,17 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 		config.Script = attr.(string)
 	}
 
-	tfTags := d.Get("tags").(*schema.Set).List()
-	tags := make([]string, len(tfTags))
-	for i, tfTag := range tfTags {
-		tags[i] = tfTag.(string)
-	}
-
-	config.Tags = tags
+	config.Tags = expandInstanceTags(d.Get("tags").(*schema.Set))
 
 	log.Printf("[INFO] creating the instance %s", d.Get("hostname").(string))
 	instance, err := apiClient.CreateInstance(config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create instance: %s", err)
+		return utils.DiagError("[ERR] failed to create instance", err)
 	}
 
 	d.SetId(instance.ID)
 
-	createStateConf := &resource.StateChangeConf{
-		Pending: []string{"BUILDING"},
-		Target:  []string{"ACTIVE"},
-		Refresh: func() (interface{}, string, error) {
-			resp, err := apiClient.GetInstance(d.Id())
-			if err != nil {
-				return 0, "", err
-			}
-			return resp, resp.Status, nil
-		},
-		Timeout:        60 * time.Minute,
-		Delay:          3 * time.Second,
-		MinTimeout:     3 * time.Second,
-		NotFoundChecks: 60,
-	}
+	createStateConf := instanceActiveStateConf(apiClient, d.Id(), "created")
 	_, err = createStateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return diag.Errorf("error waiting for instance (%s) to be created: %s", d.Id(), err)
@@ -287,19 +316,26 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 	if attr, ok := d.GetOk("firewall_id"); ok {
 		_, errInstance := apiClient.SetInstanceFirewall(d.Id(), attr.(string))
 		if errInstance != nil {
-			return diag.Errorf("[ERR] updating instance firewall: %s", err)
+			return utils.DiagError("[ERR] updating instance firewall", errInstance)
+		}
+	}
+
+	if attr, ok := d.GetOk("reserved_ip"); ok {
+		log.Printf("[INFO] assigning the reserved ip %s to the instance %s", attr.(string), d.Id())
+		if err := assignReservedIP(apiClient, attr.(string), d.Id()); err != nil {
+			return utils.DiagError("[ERR] failed to assign the reserved ip", err)
 		}
 	}
 
 	if attr, ok := d.GetOk("notes"); ok {
 		resp, err := apiClient.GetInstance(d.Id())
 		if err != nil {
-			return diag.Errorf("[ERR] getting instance: %s", err)
+			return utils.DiagError("[ERR] getting instance", err)
 		}
 		resp.Notes = attr.(string)
 		_, errInstance := apiClient.UpdateInstance(resp)
 		if errInstance != nil {
-			return diag.Errorf("[ERR] updating instance notes: %s", err)
+			return utils.DiagError("[ERR] updating instance notes", errInstance)
 		}
 	}
 
@@ -309,7 +345,7 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 // function to read the instance
 func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -324,7 +360,7 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 			return nil
 		}
 
-		return diag.Errorf("[ERR] failed to retriving the instance: %s", err)
+		return utils.DiagError("[ERR] failed to retriving the instance", err)
 	}
 
 	d.Set("hostname", resp.Hostname)
@@ -337,10 +373,14 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 	d.Set("initial_password", resp.InitialPassword)
 	d.Set("source_type", resp.SourceType)
 	d.Set("source_id", resp.SourceID)
-	d.Set("sshkey_id", resp.SSHKey)
-	d.Set("tags", resp.Tags)
-	d.Set("private_ip", resp.PrivateIP)
-	d.Set("public_ip", resp.PublicIP)
+	// the API doesn't always return the ID of the key, we keep the one in the
+	// state then, or the instance would be recreated in every plan
+	if resp.SSHKeyID != "" {
+		d.Set("sshkey_id", resp.SSHKeyID)
+	}
+	d.Set("tags", flattenInstanceTags(resp.Tags))
+	setInstanceIP(d, "private_ip", resp.PrivateIP)
+	setInstanceIP(d, "public_ip", resp.PublicIP)
 	d.Set("network_id", resp.NetworkID)
 	d.Set("firewall_id", resp.FirewallID)
 	d.Set("status", resp.Status)
@@ -348,6 +388,27 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 	d.Set("created_at", resp.CreatedAt.UTC().String())
 	d.Set("notes", resp.Notes)
 
+	// use the region of the instance, the API can return it in other format
+	if resp.Region != "" {
+		d.Set("region", utils.NormalizeRegion(resp.Region))
+	} else {
+		d.Set("region", utils.NormalizeRegion(apiClient.Region))
+	}
+
+	// the reserved ip is only reconciled if is declared, so we don't take the
+	// assignments done outside of this attribute
+	if attr, ok := d.GetOk("reserved_ip"); ok {
+		ip, err := utils.FindReservedIP(apiClient, attr.(string))
+		if err != nil {
+			return utils.DiagError("[ERR] failed to retrive the reserved ip", err)
+		}
+
+		if ip == nil || ip.AssignedTo.ID != d.Id() {
+			log.Printf("[WARN] the reserved ip %s is no longer assigned to the instance %s", attr.(string), d.Id())
+			d.Set("reserved_ip", "")
+		}
+	}
+
 	if _, ok := d.GetOk("template"); ok {
 		d.Set("template", d.Get("template").(string))
 	}
@@ -361,7 +422,7 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 // function to update a instance
 func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -378,24 +439,25 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			return diag.Errorf("[WARN] An error occurred while resizing the instance %s", d.Id())
 		}
 
-		createStateConf := &resource.StateChangeConf{
-			Pending: []string{"BUILDING"},
-			Target:  []string{"ACTIVE"},
-			Refresh: func() (interface{}, string, error) {
-				resp, err := apiClient.GetInstance(d.Id())
-				if err != nil {
-					return 0, "", err
-				}
-				return resp, resp.Status, nil
-			},
-			Timeout:        60 * time.Minute,
-			Delay:          3 * time.Second,
-			MinTimeout:     3 * time.Second,
-			NotFoundChecks: 60,
+		resizeStateConf := instanceActiveStateConf(apiClient, d.Id(), "resized")
+		_, err = resizeStateConf.WaitForStateContext(ctx)
+		if err != nil {
+			return diag.Errorf("error waiting for instance (%s) to be resized: %s", d.Id(), err)
 		}
-		_, err = createStateConf.WaitForStateContext(ctx)
+	}
+
+	// the reverse DNS (PTR record) of the public IP is updated in place
+	if d.HasChange("reverse_dns") {
+		instance, err := apiClient.GetInstance(d.Id())
 		if err != nil {
-			return diag.Errorf("error waiting for instance (%s) to be created: %s", d.Id(), err)
+			return utils.DiagError("[ERR] failed to retriving the instance", err)
+		}
+
+		instance.ReverseDNS = d.Get("reverse_dns").(string)
+
+		log.Printf("[INFO] updating the reverse DNS of the instance %s to %s", d.Id(), instance.ReverseDNS)
+		if _, err := apiClient.UpdateInstance(instance); err != nil {
+			return utils.DiagError("[ERR] an error occurred while updating the reverse DNS of the instance "+d.Id(), err)
 		}
 	}
 
@@ -413,7 +475,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		log.Printf("[INFO] adding notes to the instance %s", d.Id())
 		_, err = apiClient.UpdateInstance(instance)
 		if err != nil {
-			return diag.Errorf("[ERR] an error occurred while adding a note to the instance %s", d.Id())
+			return utils.DiagError("[ERR] an error occurred while adding a note to the instance "+d.Id(), err)
 		}
 	}
 
@@ -429,13 +491,33 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		}
 	}
 
-	// if tags is declare we update the instance with the tags
-	if d.HasChange("tags") {
-		tfTags := d.Get("tags").(*schema.Set).List()
-		tags := make([]string, len(tfTags))
-		for i, tfTag := range tfTags {
-			tags[i] = tfTag.(string)
+	// if the reserved ip change we unassign the old one and assign the new one
+	if d.HasChange("reserved_ip") {
+		oldIP, newIP := d.GetChange("reserved_ip")
+
+		if oldIP.(string) != "" {
+			log.Printf("[INFO] unassigning the reserved ip %s from the instance %s", oldIP.(string), d.Id())
+			if err := unassignReservedIP(apiClient, oldIP.(string), d.Id()); err != nil {
+				return utils.DiagError("[ERR] failed to unassign the reserved ip", err)
+			}
+		}
+
+		if newIP.(string) != "" {
+			log.Printf("[INFO] assigning the reserved ip %s to the instance %s", newIP.(string), d.Id())
+			if err := assignReservedIP(apiClient, newIP.(string), d.Id()); err != nil {
+				return utils.DiagError("[ERR] failed to assign the reserved ip", err)
+			}
 		}
+	}
+
+	// if tags is declare we update the instance with the tags, the instance
+	// is not recreated, the endpoint replace the whole set of tags
+	if d.HasChange("tags") {
+		oldTags, newTags := d.GetChange("tags")
+		added := newTags.(*schema.Set).Difference(oldTags.(*schema.Set))
+		removed := oldTags.(*schema.Set).Difference(newTags.(*schema.Set))
+
+		tags := expandInstanceTags(newTags.(*schema.Set))
 
 		instance, err := apiClient.GetInstance(d.Id())
 		if err != nil {
@@ -443,28 +525,62 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			return diag.Errorf("[ERR] instance %s not found", d.Id())
 		}
 
-		tagsToString := strings.Join(tags, " ")
-
-		log.Printf("[INFO] adding tags to the instance %s", d.Id())
-		_, err = apiClient.SetInstanceTags(instance, tagsToString)
+		log.Printf("[INFO] updating the tags of the instance %s, adding %v and removing %v", d.Id(), added.List(), removed.List())
+		_, err = apiClient.SetInstanceTags(instance, strings.Join(tags, " "))
 		if err != nil {
 			return diag.Errorf("[ERR] an error occurred while adding tags to the instance %s", d.Id())
 		}
 
+		// read back the tags, the API is the source of truth
+		instance, err = apiClient.GetInstance(d.Id())
+		if err != nil {
+			return utils.DiagError("[ERR] failed to retriving the instance", err)
+		}
+
+		if !utils.TagsEqual(instance.Tags, tags, d.Get("tags_ignore_case").(bool)) {
+			return diag.Errorf("[ERR] the tags of the instance %s were not updated, expected %v, got %v", d.Id(), tags, instance.Tags)
+		}
 	}
 
-	return resourceInstanceRead(ctx, d, m)
+	diags := resourceInstanceRead(ctx, d, m)
+
+	// the API can't change the key of an instance, the new key is only kept
+	// in the state, so we warn the instance still has the old one
+	if d.HasChange("sshkey_id") {
+		oldKey, newKey := d.GetChange("sshkey_id")
+		diags = append(diags, instanceSSHKeyChangeWarning(d.Id(), oldKey.(string), newKey.(string)))
+	}
+
+	return diags
+}
+
+// instanceSSHKeyChangeWarning returns the warning for a new sshkey_id, the
+// Civo API can't change the key of an existing instance
+func instanceSSHKeyChangeWarning(instanceID string, oldKey string, newKey string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The SSH key of the instance %s was not changed", instanceID),
+		Detail: fmt.Sprintf("The sshkey_id changed from %q to %q, but the Civo API can't change the key of an existing instance, so the instance still accepts the old key. "+
+			"To rotate the key, update the authorized_keys of the default user inside the instance", oldKey, newKey),
+	}
 }
 
 // function to delete instance
 func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
 		apiClient.Region = region.(string)
 	}
 
+	if d.Get("graceful_shutdown").(bool) {
+		log.Printf("[INFO] stopping the instance %s before deleting it", d.Id())
+		if _, err := stopInstanceAndWait(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
+			log.Printf("[WARN] the instance %s didn't stop gracefully, deleting it anyway: %s", d.Id(), err)
+		}
+	}
+
 	log.Printf("[INFO] deleting the instance %s", d.Id())
 	_, err := apiClient.DeleteInstance(d.Id())
 	if err != nil {
@@ -472,3 +588,211 @@ func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	return nil
 }
+
+// assignReservedIP assign the reserved IP to the instance
+func assignReservedIP(apiClient *civogo.Client, search string, instanceID string) error {
+	ip, err := utils.FindReservedIP(apiClient, search)
+	if err != nil {
+		return err
+	}
+	if ip == nil {
+		return fmt.Errorf("the reserved ip %s was not found", search)
+	}
+
+	if ip.AssignedTo.ID == instanceID {
+		return nil
+	}
+
+	return utils.AssignReservedIP(apiClient, ip.ID, instanceID)
+}
+
+// unassignReservedIP unassign the reserved IP if it is still assigned to the instance
+func unassignReservedIP(apiClient *civogo.Client, search string, instanceID string) error {
+	ip, err := utils.FindReservedIP(apiClient, search)
+	if err != nil {
+		return err
+	}
+	if ip == nil || ip.AssignedTo.ID != instanceID {
+		return nil
+	}
+
+	return utils.UnassignReservedIP(apiClient, ip.ID)
+}
+
+// setInstanceIP set the IP address read from the API, logging when Civo changed it,
+// unless ignore_ip_changes is enabled and the instance already have an address
+func setInstanceIP(d *schema.ResourceData, key string, ip string) {
+	current := d.Get(key).(string)
+	if current == "" || current == ip {
+		d.Set(key, ip)
+		return
+	}
+
+	if d.Get("ignore_ip_changes").(bool) {
+		log.Printf("[INFO] the %s of the instance %s changed from %s to %s, keeping %s because ignore_ip_changes is enabled", key, d.Id(), current, ip, current)
+		return
+	}
+
+	log.Printf("[WARN] the %s of the instance %s changed from %s to %s, the resources referencing it will be updated", key, d.Id(), current, ip)
+	d.Set(key, ip)
+}
+
+// expandInstanceTags convert the set of tags to the list used by the API
+func expandInstanceTags(set *schema.Set) []string {
+	tags := make([]string, 0, set.Len())
+	for _, tag := range set.List() {
+		tags = append(tags, tag.(string))
+	}
+	return tags
+}
+
+// flattenInstanceTags convert the tags returned by the API to a set,
+// ignoring the empty tags the API can return
+func flattenInstanceTags(tags []string) *schema.Set {
+	set := schema.NewSet(utils.HashTag(false), []interface{}{})
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			set.Add(tag)
+		}
+	}
+	return set
+}
+
+// custom diff for the instance, we check the pinned disk image version exists
+// and the backend can resize an instance live only if the new size has the same
+// or a bigger disk, so we reject the rest at plan time
+func customizeDiffInstance(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := checkInstanceDiskImageVersion(d, m); err != nil {
+		return err
+	}
+
+	if d.Id() == "" || !d.HasChange("size") || !d.NewValueKnown("size") {
+		return nil
+	}
+
+	// use a client for the region of the instance, the diff can't change the shared client
+	region, _ := d.Get("region").(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+
+	oldSizeName, newSizeName := d.GetChange("size")
+
+	oldSize, err := apiClient.FindInstanceSizes(oldSizeName.(string))
+	if err != nil {
+		return fmt.Errorf("[ERR] failed to get the size %s: %s", oldSizeName.(string), err)
+	}
+
+	newSize, err := apiClient.FindInstanceSizes(newSizeName.(string))
+	if err != nil {
+		return fmt.Errorf("[ERR] failed to get the size %s: %s", newSizeName.(string), err)
+	}
+
+	if newSize.DiskGigabytes < oldSize.DiskGigabytes {
+		return fmt.Errorf("[ERR] the instance can't be resized from %s to %s, the disk can't be reduced from %dGB to %dGB", oldSize.Name, newSize.Name, oldSize.DiskGigabytes, newSize.DiskGigabytes)
+	}
+
+	return nil
+}
+
+// checkInstanceDiskImageVersion check at plan time the pinned version of the disk
+// image exists, and warn if the disk image resolves now to a different version
+// than the one the instance was created from. The warning is log-only, the
+// instance doesn't change so there is no apply to return it from
+func checkInstanceDiskImageVersion(d *schema.ResourceDiff, m interface{}) error {
+	diskImage, ok := d.GetOk("disk_image")
+	if !ok || !d.NewValueKnown("disk_image") || !d.NewValueKnown("disk_image_version") {
+		return nil
+	}
+
+	// use a client for the region of the instance, the diff can't change the shared client
+	region, _ := d.Get("region").(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+
+	if d.Id() == "" || d.HasChange("disk_image_version") {
+		version := d.Get("disk_image_version").(string)
+		if version == "" {
+			return nil
+		}
+		if _, err := findInstanceDiskImage(apiClient, diskImage.(string), version); err != nil {
+			return fmt.Errorf("[ERR] the version %s of the disk image %s was not found: %s", version, diskImage.(string), err)
+		}
+		return nil
+	}
+
+	resolved, err := apiClient.FindDiskImage(diskImage.(string))
+	if err != nil {
+		log.Printf("[DEBUG] unable to resolve the disk image %s to check its version: %s", diskImage.(string), err)
+		return nil
+	}
+
+	if imageID := d.Get("disk_image_id").(string); imageID != "" && resolved.ID != imageID {
+		logPlanWarning("the disk image %s now resolves to %s (version %s), the instance %s was created from %s (version %s), pin disk_image_version to keep the builds reproducible",
+			diskImage.(string), resolved.ID, resolved.Version, d.Id(), imageID, d.Get("disk_image_version").(string))
+	}
+
+	return nil
+}
+
+// findInstanceDiskImage find the disk image by name or ID, if the version is
+// not empty the disk image must have that version
+func findInstanceDiskImage(apiClient *civogo.Client, search string, version string) (*civogo.DiskImage, error) {
+	if version == "" {
+		return apiClient.FindDiskImage(search)
+	}
+
+	diskImages, err := apiClient.ListDiskImages()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{}
+	for _, diskImage := range diskImages {
+		if diskImage.Name != search && diskImage.ID != search {
+			continue
+		}
+		if diskImage.Version == version {
+			image := diskImage
+			return &image, nil
+		}
+		versions = append(versions, diskImage.Version)
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("the disk image %s doesn't exist", search)
+	}
+
+	return nil, fmt.Errorf("the disk image %s doesn't have the version %s, the available versions are %s", search, version, strings.Join(versions, ", "))
+}
+
+// instancePendingStatuses are the transient statuses of an instance on the way
+// to ACTIVE, the wait keeps polling while the instance is in one of them
+var instancePendingStatuses = []string{"BUILD_PENDING", "BUILDING", "INSTALLING", "BOOTING", "STARTING", "REBOOTING"}
+
+// instanceFailedStatuses are the statuses an instance never leaves by itself,
+// the wait stops with an error instead of polling until the timeout
+var instanceFailedStatuses = []string{"FAILED", "ERROR", "BUILD_FAILED"}
+
+// instanceActiveStateConf returns the wait until the instance is ACTIVE, used
+// after the create and the resize of the instance. The operation is what
+// happened to the instance, like created or resized, for the errors
+func instanceActiveStateConf(apiClient *civogo.Client, instanceID string, operation string) *resource.StateChangeConf {
+	return &resource.StateChangeConf{
+		Pending: instancePendingStatuses,
+		Target:  []string{"ACTIVE"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetInstance(instanceID)
+			if err != nil {
+				return 0, "", err
+			}
+			for _, status := range instanceFailedStatuses {
+				if resp.Status == status {
+					return resp, resp.Status, fmt.Errorf("the instance %s failed to be %s, its status is %s", instanceID, operation, resp.Status)
+				}
+			}
+			return resp, resp.Status, nil
+		},
+		Timeout:        60 * time.Minute,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+}
@@ -1,12 +1,20 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -52,6 +60,7 @@ func TestAccCivoInstance_basic(t *testing.T) {
 
 func TestAccCivoInstanceSize_update(t *testing.T) {
 	var instance civogo.Instance
+	var resizedInstance civogo.Instance
 
 	// generate a random name for each test run
 	resName := "civo_instance.foobar"
@@ -84,8 +93,9 @@ func TestAccCivoInstanceSize_update(t *testing.T) {
 				// use a dynamic configuration with the random name from above
 				Config: testAccCheckCivoInstanceConfigUpdates(instanceHostname),
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckCivoInstanceResourceExists(resName, &instance),
-					testAccCheckCivoInstanceUpdated(&instance, instanceHostname),
+					testAccCheckCivoInstanceResourceExists(resName, &resizedInstance),
+					testAccCheckCivoInstanceUpdated(&resizedInstance, instanceHostname),
+					testAccCheckCivoInstanceNotRecreated(&instance, &resizedInstance),
 					resource.TestCheckResourceAttr(resName, "hostname", instanceHostname),
 					resource.TestCheckResourceAttr(resName, "size", "g2.large"),
 					resource.TestCheckResourceAttr(resName, "initial_user", "civo"),
@@ -103,6 +113,33 @@ func TestAccCivoInstanceSize_update(t *testing.T) {
 	})
 }
 
+func TestAccCivoInstanceSize_downgrade(t *testing.T) {
+	var instance civogo.Instance
+
+	// generate a random name for each test run
+	resName := "civo_instance.foobar"
+	var instanceHostname = acctest.RandomWithPrefix("tf-test") + ".example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoInstanceConfigUpdates(instanceHostname),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoInstanceResourceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "size", "g2.large"),
+				),
+			},
+			{
+				Config:      testAccCheckCivoInstanceConfigBasic(instanceHostname),
+				ExpectError: regexp.MustCompile("the disk can't be reduced"),
+			},
+		},
+	})
+}
+
 func TestAccCivoInstanceNotes_update(t *testing.T) {
 	var instance civogo.Instance
 
@@ -154,6 +191,14 @@ func TestAccCivoInstanceNotes_update(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resName, "created_at"),
 				),
 			},
+			{
+				Config: testAccCheckCivoInstanceConfigReverseDNS(instanceHostname),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoInstanceResourceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "hostname", instanceHostname),
+					resource.TestCheckResourceAttr(resName, "reverse_dns", "ptr."+instanceHostname),
+				),
+			},
 		},
 	})
 }
@@ -214,6 +259,47 @@ func TestAccCivoInstanceFirewall_update(t *testing.T) {
 	})
 }
 
+func TestAccCivoInstanceTags_update(t *testing.T) {
+	var instance civogo.Instance
+	var updatedInstance civogo.Instance
+
+	// generate a random name for each test run
+	resName := "civo_instance.foobar"
+	var instanceHostname = acctest.RandomWithPrefix("tf-test") + ".example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoInstanceConfigTags(instanceHostname, `"web", "nginx"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoInstanceResourceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags.*", "web"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags.*", "nginx"),
+				),
+			},
+			{
+				Config: testAccCheckCivoInstanceConfigTags(instanceHostname, `"python", "web"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoInstanceResourceExists(resName, &updatedInstance),
+					testAccCheckCivoInstanceNotRecreated(&instance, &updatedInstance),
+					resource.TestCheckResourceAttr(resName, "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags.*", "web"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags.*", "python"),
+				),
+			},
+			{
+				// only the order change, so there is nothing to update
+				Config:   testAccCheckCivoInstanceConfigTags(instanceHostname, `"web", "python"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func testAccCheckCivoInstanceValues(instance *civogo.Instance, name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if instance.Hostname != name {
@@ -233,7 +319,7 @@ func testAccCheckCivoInstanceResourceExists(n string, instance *civogo.Instance)
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.GetInstance(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Instance not found: (%s) %s", rs.Primary.ID, err)
@@ -256,8 +342,17 @@ func testAccCheckCivoInstanceUpdated(instance *civogo.Instance, name string) res
 	}
 }
 
+func testAccCheckCivoInstanceNotRecreated(before, after *civogo.Instance) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.ID != after.ID {
+			return fmt.Errorf("the instance was recreated, expected ID \"%s\", got: %#v", before.ID, after.ID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckCivoInstanceDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_instance" {
@@ -298,6 +393,16 @@ resource "civo_instance" "foobar" {
 }`, hostname)
 }
 
+func testAccCheckCivoInstanceConfigReverseDNS(hostname string) string {
+	return fmt.Sprintf(`
+resource "civo_instance" "foobar" {
+	hostname = "%s"
+	size = "g2.xsmall"
+	notes = "the_test_notes"
+	reverse_dns = "ptr.%s"
+}`, hostname, hostname)
+}
+
 func testAccCheckCivoInstanceConfigFirewall(hostname string) string {
 	return fmt.Sprintf(`
 resource "civo_firewall" "foobar" {
@@ -310,3 +415,373 @@ resource "civo_instance" "foobar" {
 	firewall_id = civo_firewall.foobar.id
 }`, hostname)
 }
+
+func testAccCheckCivoInstanceConfigTags(hostname string, tags string) string {
+	return fmt.Sprintf(`
+resource "civo_instance" "foobar" {
+	hostname = "%s"
+	size = "g2.xsmall"
+	tags = [%s]
+}`, hostname, tags)
+}
+
+func TestSetInstanceIP(t *testing.T) {
+	d := resourceInstance().TestResourceData()
+	d.SetId("12345")
+
+	setInstanceIP(d, "public_ip", "1.2.3.4")
+	if ip := d.Get("public_ip").(string); ip != "1.2.3.4" {
+		t.Fatalf("expected the public_ip 1.2.3.4, got %s", ip)
+	}
+
+	setInstanceIP(d, "public_ip", "5.6.7.8")
+	if ip := d.Get("public_ip").(string); ip != "5.6.7.8" {
+		t.Fatalf("expected the changed public_ip 5.6.7.8, got %s", ip)
+	}
+
+	d.Set("ignore_ip_changes", true)
+	setInstanceIP(d, "public_ip", "9.9.9.9")
+	if ip := d.Get("public_ip").(string); ip != "5.6.7.8" {
+		t.Fatalf("expected the public_ip to be kept with ignore_ip_changes, got %s", ip)
+	}
+}
+
+func TestFindInstanceDiskImage(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/disk_images": `[
+			{"id": "11111", "name": "ubuntu-focal", "version": "20.04"},
+			{"id": "22222", "name": "ubuntu-jammy", "version": "22.04"}
+		]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	image, err := findInstanceDiskImage(client, "ubuntu-jammy", "22.04")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if image.ID != "22222" {
+		t.Fatalf("expected the disk image 22222, got %s", image.ID)
+	}
+
+	if _, err := findInstanceDiskImage(client, "ubuntu-jammy", "22.10"); err == nil {
+		t.Fatal("expected an error for a version that doesn't exist")
+	}
+
+	if _, err := findInstanceDiskImage(client, "debian-10", "10"); err == nil {
+		t.Fatal("expected an error for a disk image that doesn't exist")
+	}
+}
+
+func TestInstanceInitialPasswordSensitive(t *testing.T) {
+	schemas := map[string]map[string]*schema.Schema{
+		"civo_instance resource":    resourceInstance().Schema,
+		"civo_instance data source": dataSourceInstance().Schema,
+		"civo_instances records":    instancesSchema(),
+	}
+
+	for name, s := range schemas {
+		password, ok := s["initial_password"]
+		if !ok {
+			t.Fatalf("expected initial_password in the %s", name)
+		}
+		if !password.Sensitive {
+			t.Fatalf("expected initial_password of the %s to be sensitive", name)
+		}
+	}
+}
+
+func TestInstanceActiveStateConf(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []string
+		err      string
+	}{
+		{"build", []string{"BUILD_PENDING", "BUILDING", "INSTALLING", "BOOTING", "ACTIVE"}, ""},
+		{"failed", []string{"BUILD_PENDING", "BUILDING", "FAILED"}, "the instance 12345 failed to be resized, its status is FAILED"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/v2/instances/12345" {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				status := c.statuses[len(c.statuses)-1]
+				if calls < len(c.statuses) {
+					status = c.statuses[calls]
+				}
+				calls++
+				fmt.Fprintf(rw, `{"id": "12345", "hostname": "web", "status": %q}`, status)
+			}))
+			defer server.Close()
+
+			client, err := civogo.NewClientForTestingWithServer(server)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			stateConf := instanceActiveStateConf(client, "12345", "resized")
+			stateConf.Delay = 0
+			stateConf.MinTimeout = time.Millisecond
+			stateConf.PollInterval = time.Millisecond
+			stateConf.Timeout = 10 * time.Second
+
+			_, err = stateConf.WaitForStateContext(context.Background())
+			if c.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if calls != len(c.statuses) {
+					t.Fatalf("expected %d calls until ACTIVE, got %d", len(c.statuses), calls)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected the error %q, got %v", c.err, err)
+			}
+			if calls != len(c.statuses) {
+				t.Fatalf("expected the wait to stop at the failed status after %d calls, got %d", len(c.statuses), calls)
+			}
+		})
+	}
+}
+
+func TestResourceInstanceRead_sshKey(t *testing.T) {
+	if resourceInstance().Schema["sshkey_id"].ForceNew {
+		t.Fatal("expected a new sshkey_id not to recreate the instance")
+	}
+
+	cases := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"key returned by the API", `"ssh_key_id": "new-key"`, "new-key"},
+		{"key not returned by the API", `"ssh_key_id": ""`, "old-key"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/v2/instances/12345" {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprintf(rw, `{"id": "12345", "hostname": "web", "status": "ACTIVE", %s}`, c.response)
+			}))
+			defer server.Close()
+
+			client, err := civogo.NewClientForTestingWithServer(server)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			d := resourceInstance().TestResourceData()
+			d.SetId("12345")
+			d.Set("sshkey_id", "old-key")
+
+			if diags := resourceInstanceRead(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if key := d.Get("sshkey_id").(string); key != c.expected {
+				t.Fatalf("expected the sshkey_id %s, got %s", c.expected, key)
+			}
+		})
+	}
+}
+
+func TestResourceInstanceDiff_sshKey(t *testing.T) {
+	keyID := "5b3b4a3e-9c5d-4b2a-8f4e-0c1d2e3f4a5b"
+	otherKeyID := "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"
+
+	cases := []struct {
+		name   string
+		state  string
+		config string
+		change bool
+	}{
+		{"legacy public key in the state", "ssh-rsa AAAAB3NzaC1yc2E user@host", keyID, true},
+		{"same key ID", keyID, keyID, false},
+		{"new key ID", keyID, otherKeyID, true},
+		{"key added", "", keyID, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "12345",
+				Attributes: map[string]string{
+					"id":                 "12345",
+					"hostname":           "web",
+					"sshkey_id":          c.state,
+					"region":             "LON1",
+					"public_ip":          "10.0.0.1",
+					"private_ip":         "192.168.1.2",
+					"initial_user":       "civo",
+					"size":               "g3.xsmall",
+					"public_ip_required": "create",
+					"ignore_ip_changes":  "false",
+					"graceful_shutdown":  "false",
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"hostname":  "web",
+				"sshkey_id": c.config,
+			})
+
+			diff, err := resourceInstance().Diff(context.Background(), state, config, &providerMeta{client: &civogo.Client{}})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if diff != nil && diff.RequiresNew() {
+				t.Fatalf("expected the instance not to be recreated, got the diff %v", diff)
+			}
+			if change := diff != nil && diff.Attributes["sshkey_id"] != nil; change != c.change {
+				t.Fatalf("expected a change of sshkey_id %t, got the diff %v", c.change, diff)
+			}
+		})
+	}
+}
+
+func TestResourceInstanceDiff_resizeRegion(t *testing.T) {
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		regions = append(regions, req.URL.Query().Get("region"))
+		fmt.Fprint(rw, `[{"name": "g3.xsmall", "disk_size_gigabytes": 25}, {"name": "g3.small", "disk_size_gigabytes": 25}]`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	state := &terraform.InstanceState{
+		ID: "12345",
+		Attributes: map[string]string{
+			"id":                 "12345",
+			"hostname":           "web",
+			"region":             "NYC1",
+			"public_ip":          "10.0.0.1",
+			"private_ip":         "192.168.1.2",
+			"initial_user":       "civo",
+			"size":               "g3.xsmall",
+			"public_ip_required": "create",
+			"ignore_ip_changes":  "false",
+			"graceful_shutdown":  "false",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"hostname": "web",
+		"region":   "NYC1",
+		"size":     "g3.small",
+	})
+
+	if _, err := resourceInstance().Diff(context.Background(), state, config, &providerMeta{client: client}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(regions) == 0 || regions[0] != "NYC1" {
+		t.Fatalf("expected the sizes to be read in the region of the instance, got %v", regions)
+	}
+	if client.Region != "LON1" {
+		t.Fatalf("expected the client of the provider to keep its region, got %s", client.Region)
+	}
+}
+
+func TestResourceInstanceDelete_gracefulShutdown(t *testing.T) {
+	cases := []struct {
+		name     string
+		stops    bool
+		timeout  time.Duration
+		expected string
+	}{
+		{"graceful", true, 10 * time.Second, "GET /v2/instances/12345, PUT /v2/instances/12345/stop, GET /v2/instances/12345, DELETE /v2/instances/12345"},
+		{"timeout", false, time.Second, "GET /v2/instances/12345, PUT /v2/instances/12345/stop, DELETE /v2/instances/12345"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var requests []string
+			status := "ACTIVE"
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				switch req.Method {
+				case http.MethodGet:
+					fmt.Fprintf(rw, `{"id": "12345", "hostname": "web", "status": "%s"}`, status)
+				case http.MethodPut:
+					if c.stops {
+						status = "SHUTOFF"
+					}
+					fmt.Fprint(rw, `{"result": "success"}`)
+				default:
+					fmt.Fprint(rw, `{"result": "success"}`)
+				}
+			}))
+			defer server.Close()
+
+			client, err := civogo.NewClientForTestingWithServer(server)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			// the instance that doesn't stop before the delete timeout is deleted anyway
+			r := resourceInstance()
+			r.Timeouts.Delete = &c.timeout
+			d := r.Data(nil)
+			d.SetId("12345")
+			d.Set("graceful_shutdown", true)
+
+			if diags := resourceInstanceDelete(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+				t.Fatalf("err: %v", diags)
+			}
+			if strings.Join(requests, ", ") != c.expected {
+				t.Fatalf("expected the requests %s, got %v", c.expected, requests)
+			}
+		})
+	}
+}
+
+func TestResourceInstanceDiff_tagsIgnoreCase(t *testing.T) {
+	for _, ignoreCase := range []bool{true, false} {
+		t.Run(fmt.Sprintf("tags_ignore_case %t", ignoreCase), func(t *testing.T) {
+			hash := strconv.Itoa(schema.HashString("Web"))
+			state := &terraform.InstanceState{
+				ID: "12345",
+				Attributes: map[string]string{
+					"id":                 "12345",
+					"hostname":           "web",
+					"region":             "LON1",
+					"public_ip":          "10.0.0.1",
+					"private_ip":         "192.168.1.2",
+					"initial_user":       "civo",
+					"size":               "g3.xsmall",
+					"public_ip_required": "create",
+					"ignore_ip_changes":  "false",
+					"graceful_shutdown":  "false",
+					"tags_ignore_case":   strconv.FormatBool(ignoreCase),
+					"tags.#":             "1",
+					"tags." + hash:       "Web",
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"hostname":         "web",
+				"tags":             []interface{}{"web"},
+				"tags_ignore_case": ignoreCase,
+			})
+
+			diff, err := resourceInstance().Diff(context.Background(), state, config, &providerMeta{client: &civogo.Client{}})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if change := diff != nil && !diff.Empty(); change == ignoreCase {
+				t.Fatalf("expected a change of the case of the tag %t, got the diff %v", !ignoreCase, diff)
+			}
+		})
+	}
+}
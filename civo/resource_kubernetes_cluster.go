@@ -2,8 +2,13 @@ package civo
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/civo/civogo"
@@ -28,10 +33,11 @@ func resourceKubernetesCluster() *schema.Resource {
 				ValidateFunc: utils.ValidateNameSize,
 			},
 			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "The region for the cluster, if not declare we use the region in declared in the provider",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The region for the cluster, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 			"network_id": {
 				Type:        schema.TypeString,
@@ -60,17 +66,27 @@ func resourceKubernetesCluster() *schema.Resource {
 				Computed:    true,
 				Description: "The version of k3s to install (optional, the default is currently the latest available)",
 			},
+			// The backend can't change the cni of a running cluster, so we
+			// recreate the cluster if the cni changes
 			"cni": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				Description:  "The cni for the k3s to install (the default is `flannel`) valid options are `cilium` or `flannel`",
+				ForceNew:     true,
+				Description:  "The cni for the k3s to install (the default is `flannel`) valid options are `cilium` or `flannel`, changing it will recreate the cluster",
 				ValidateFunc: utils.ValidateCNIName,
 			},
 			"tags": {
-				Type:        schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: utils.DiffSuppressTagsIgnoreCaseFrom("tags_ignore_case"),
+				Description:      "Space separated list of tags, to be used freely as required, the order of the tags is ignored and their case too unless `tags_ignore_case` is false",
+			},
+			"tags_ignore_case": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Space separated list of tags, to be used freely as required",
+				Default:     true,
+				Description: "If enabled, a tag that only changed its case, like `Web` and `web`, is not a change of the tags",
 			},
 			"applications": {
 				Type:     schema.TypeString,
@@ -86,8 +102,19 @@ func resourceKubernetesCluster() *schema.Resource {
 			},
 			"firewall_id": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The existing firewall ID to use for this cluster",
+				Optional:    true,
+				Computed:    true,
+				Description: "The existing firewall ID to use for this cluster, it must be in the same region and network of the cluster. If not declared, a new firewall with the default rules is created for the cluster and deleted with it",
+			},
+			"allow_api_access_from": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsCIDR,
+				},
+				Description: "The CIDRs allowed to reach the Kubernetes API (tcp port 6443), a rule with the label `" + kubernetesAPIAccessLabelPrefix + "-<cluster id>` is managed in the firewall of the cluster for them. " +
+					"The rule is kept in sync with this list and removed when the list is empty or the cluster is destroyed",
 			},
 			// Computed resource
 			"instances":              instanceSchema(),
@@ -109,6 +136,20 @@ func resourceKubernetesCluster() *schema.Resource {
 				Sensitive:   true,
 				Description: "The kubeconfig of the cluster",
 			},
+			"kubeconfig_path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description: "Path of a file to write the kubeconfig of the cluster to after it is created, with 0600 permissions. The file is removed when the cluster is destroyed. " +
+					"The kubeconfig has the admin credentials of the cluster, so the file must be kept out of version control and shared machines",
+			},
+			"cleanup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "If enabled, when the cluster is destroyed the provider waits until the cluster is gone and then deletes the load balancers and volumes with the ID of the cluster, " +
+					"the ones created in Civo by the controllers of the cluster for the `LoadBalancer` services and the persistent volumes. Nothing else is deleted",
+			},
 			"api_endpoint": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -129,6 +170,11 @@ func resourceKubernetesCluster() *schema.Resource {
 				Computed:    true,
 				Description: "The timestamp when the cluster was created",
 			},
+			"firewall_created": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the firewall was created for the cluster because `firewall_id` was not declared, in that case it is deleted with the cluster",
+			},
 		},
 		CreateContext: resourceKubernetesClusterCreate,
 		ReadContext:   resourceKubernetesClusterRead,
@@ -137,6 +183,10 @@ func resourceKubernetesCluster() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffKubernetesCluster,
 	}
 }
 
@@ -210,7 +260,7 @@ func nodePoolSchema() *schema.Schema {
 				"size": {
 					Type:        schema.TypeString,
 					Required:    true,
-					Description: "Size of the nodes in the nodepool",
+					Description: "Size of the nodes in the nodepool, it is checked at plan time against the sizes available in the region",
 				},
 				"instance_names": {
 					Type:        schema.TypeSet,
@@ -218,12 +268,35 @@ func nodePoolSchema() *schema.Schema {
 					Elem:        &schema.Schema{Type: schema.TypeString},
 					Description: "Instance names in the nodepool",
 				},
-				"instances": instanceSchema(),
+				"instances": nodePoolInstanceSchema(),
 			},
 		},
 	}
 }
 
+// schema for the instances of a node pool, they also have the ID and the
+// IPs of the instance, so firewall rules can be scoped to the nodes
+func nodePoolInstanceSchema() *schema.Schema {
+	s := instanceSchema()
+	fields := s.Elem.(*schema.Resource).Schema
+	fields["instance_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Instance's ID",
+	}
+	fields["public_ip"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Instance's public IP address",
+	}
+	fields["private_ip"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Instance's private IP address, it is empty if the instance is not found in the list of instances of the region",
+	}
+	return s
+}
+
 // schema for the application in the cluster
 func applicationSchema() *schema.Schema {
 	return &schema.Schema{
@@ -258,7 +331,7 @@ func applicationSchema() *schema.Schema {
 
 // function to create a new cluster
 func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -281,9 +354,9 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 	if networtID, ok := d.GetOk("network_id"); ok {
 		config.NetworkID = networtID.(string)
 	} else {
-		defaultNetwork, err := apiClient.GetDefaultNetwork()
+		defaultNetwork, err := getDefaultNetwork(apiClient)
 		if err != nil {
-			return diag.Errorf("[ERR] failed to get the default network: %s", err)
+			return utils.DiagError("[ERR] failed to get the default network", err)
 		}
 		config.NetworkID = defaultNetwork.ID
 	}
@@ -324,6 +397,17 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 		}
 
 		config.InstanceFirewall = firewallID
+		d.Set("firewall_created", false)
+	} else {
+		// like the network, if there is no firewall we create one for the cluster
+		log.Printf("[INFO] creating a firewall for the kubernetes cluster %s", config.Name)
+		firewall, err := apiClient.NewFirewall(fmt.Sprintf("k8s-%s", config.Name), config.NetworkID, nil)
+		if err != nil {
+			return utils.DiagError("[ERR] failed to create the firewall for the kubernetes cluster", err)
+		}
+
+		config.InstanceFirewall = firewall.ID
+		d.Set("firewall_created", true)
 	}
 
 	pools := expandNodePools(d.Get("pools").([]interface{}))
@@ -333,11 +417,18 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 	log.Printf("[INFO] kubernertes config %+v", config)
 	resp, err := apiClient.NewKubernetesClusters(config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create the kubernetes cluster: %s", err)
+		deleteCreatedKubernetesFirewall(d, apiClient, config.InstanceFirewall)
+		return utils.DiagError("[ERR] failed to create the kubernetes cluster", err)
 	}
 
 	d.SetId(resp.ID)
 
+	// the rule has the ID of the cluster in its label, it is added before the
+	// cluster is built so kubectl works as soon as it is ready
+	if err := syncKubernetesAPIAccessRule(apiClient, config.InstanceFirewall, d.Id(), expandStringSet(d.Get("allow_api_access_from").(*schema.Set))); err != nil {
+		return diag.Errorf("[ERR] failed to allow the access to the API of the kubernetes cluster: %s", err)
+	}
+
 	createStateConf := &resource.StateChangeConf{
 		Pending: []string{"BUILDING"},
 		Target:  []string{"ACTIVE"},
@@ -353,18 +444,25 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 		MinTimeout:     3 * time.Second,
 		NotFoundChecks: 10,
 	}
-	_, err = createStateConf.WaitForStateContext(context.Background())
+	cluster, err := createStateConf.WaitForStateContext(context.Background())
 	if err != nil {
 		return diag.Errorf("error waiting for cluster (%s) to be created: %s", d.Id(), err)
 	}
 
+	if path, ok := d.GetOk("kubeconfig_path"); ok {
+		log.Printf("[INFO] writing the kubeconfig of the kubernetes cluster %s to %s", d.Id(), path.(string))
+		if err := writeKubeconfigFile(path.(string), cluster.(*civogo.KubernetesCluster).KubeConfig); err != nil {
+			return diag.Errorf("[ERR] failed to write the kubeconfig of the kubernetes cluster: %s", err)
+		}
+	}
+
 	return resourceKubernetesClusterRead(ctx, d, m)
 
 }
 
 // function to read the kubernetes cluster
 func resourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -378,11 +476,11 @@ func resourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData,
 			d.SetId("")
 			return nil
 		}
-		return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
+		return utils.DiagError("[ERR] failed to find the kubernetes cluster", err)
 	}
 
 	d.Set("name", resp.Name)
-	d.Set("region", apiClient.Region)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
 	d.Set("network_id", resp.NetworkID)
 	d.Set("num_target_nodes", resp.NumTargetNode)
 	d.Set("target_nodes_size", resp.TargetNodeSize)
@@ -399,11 +497,19 @@ func resourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("created_at", resp.CreatedAt.UTC().String())
 	d.Set("firewall_id", resp.FirewallID)
 
+	// the rule can be changed or deleted outside of terraform, the firewall
+	// can be gone if it was replaced, in that case the next apply adds it again
+	if apiAccess, err := readKubernetesAPIAccessRule(apiClient, resp.FirewallID, d.Id()); err != nil {
+		log.Printf("[WARN] unable to read the API access rule of the kubernetes cluster %s: %s", d.Id(), err)
+	} else {
+		d.Set("allow_api_access_from", apiAccess)
+	}
+
 	if err := d.Set("instances", flattenInstances(resp.Instances)); err != nil {
 		return diag.Errorf("[ERR] error retrieving the instances for kubernetes cluster error: %#v", err)
 	}
 
-	if err := d.Set("pools", flattenNodePool(resp, d)); err != nil {
+	if err := d.Set("pools", flattenNodePool(resp, d, kubernetesNodesPrivateIP(apiClient, resp))); err != nil {
 		return diag.Errorf("[ERR] error retrieving the pool for kubernetes cluster error: %#v", err)
 	}
 
@@ -416,7 +522,7 @@ func resourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData,
 
 // function to update the kubernetes cluster
 func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -429,13 +535,79 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("[ERR] Network change (%q) for existing cluster is not available at this moment", "network_id")
 	}
 
+	var diags diag.Diagnostics
+
 	if d.HasChange("firewall_id") {
-		return diag.Errorf("[ERR] Firewall change (%q) for existing cluster is not available at this moment", "firewall_id")
+		firewallID := d.Get("firewall_id").(string)
+		oldFirewallID, _ := d.GetChange("firewall_id")
+		firewallCreated := d.Get("firewall_created").(bool)
+
+		log.Printf("[INFO] changing the firewall of the kubernetes cluster %s to %s", d.Id(), firewallID)
+		_, err := apiClient.UpdateKubernetesCluster(d.Id(), &civogo.KubernetesClusterConfig{
+			InstanceFirewall: firewallID,
+			Region:           apiClient.Region,
+		})
+		if err != nil {
+			return utils.DiagError("[ERR] failed to change the firewall of the kubernetes cluster", err)
+		}
+
+		// the API can ignore the change, so we check the cluster uses the new firewall
+		resp, err := apiClient.GetKubernetesCluster(d.Id())
+		if err != nil {
+			return utils.DiagError("[ERR] failed to find the kubernetes cluster", err)
+		}
+		if resp.FirewallID != firewallID {
+			return diag.Errorf("[ERR] the firewall of the kubernetes cluster %s was not changed, the API doesn't support changing the firewall of this cluster", d.Id())
+		}
+
+		d.Set("firewall_created", false)
+
+		if firewallCreated {
+			// the firewall created for the cluster is not used anymore
+			log.Printf("[INFO] deleting the firewall %s created for the kubernetes cluster %s", oldFirewallID.(string), d.Id())
+			if _, err := apiClient.DeleteFirewall(oldFirewallID.(string)); err != nil && !utils.IsNotFoundError(err) {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("The firewall %s created for the kubernetes cluster was not deleted", oldFirewallID.(string)),
+					Detail:   fmt.Sprintf("The cluster uses the firewall %s now, but the previous firewall can't be deleted: %s. Delete it manually.", firewallID, err),
+				})
+			}
+		} else if err := syncKubernetesAPIAccessRule(apiClient, oldFirewallID.(string), d.Id(), nil); err != nil {
+			// the rule is moved to the new firewall with the rest of the access
+			log.Printf("[WARN] unable to remove the API access rule from the previous firewall %s: %s", oldFirewallID.(string), err)
+		}
+	}
+
+	if d.HasChange("allow_api_access_from") || d.HasChange("firewall_id") {
+		firewallID := d.Get("firewall_id").(string)
+		log.Printf("[INFO] updating the API access rule of the kubernetes cluster %s in the firewall %s", d.Id(), firewallID)
+		if err := syncKubernetesAPIAccessRule(apiClient, firewallID, d.Id(), expandStringSet(d.Get("allow_api_access_from").(*schema.Set))); err != nil {
+			return diag.Errorf("[ERR] failed to update the access to the API of the kubernetes cluster: %s", err)
+		}
+	}
+
+	if d.HasChange("kubeconfig_path") {
+		oldPath, newPath := d.GetChange("kubeconfig_path")
+		if err := removeKubeconfigFile(oldPath.(string)); err != nil {
+			return diag.Errorf("[ERR] failed to remove the kubeconfig file of the kubernetes cluster: %s", err)
+		}
+
+		if newPath.(string) != "" {
+			resp, err := apiClient.GetKubernetesCluster(d.Id())
+			if err != nil {
+				return utils.DiagError("[ERR] failed to find the kubernetes cluster", err)
+			}
+
+			log.Printf("[INFO] writing the kubeconfig of the kubernetes cluster %s to %s", d.Id(), newPath.(string))
+			if err := writeKubeconfigFile(newPath.(string), resp.KubeConfig); err != nil {
+				return diag.Errorf("[ERR] failed to write the kubeconfig of the kubernetes cluster: %s", err)
+			}
+		}
 	}
 
 	// Update the node pool if necessary
 	if !d.HasChange("node_pool") {
-		return resourceKubernetesClusterRead(ctx, d, m)
+		return append(diags, resourceKubernetesClusterRead(ctx, d, m)...)
 	}
 
 	if d.HasChange("pools") {
@@ -451,7 +623,7 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 		config.Region = apiClient.Region
 		kubernetesCluster, err := apiClient.FindKubernetesCluster(d.Id())
 		if err != nil {
-			return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
+			return utils.DiagError("[ERR] failed to find the kubernetes cluster", err)
 		}
 
 		targetNodePool := ""
@@ -491,7 +663,7 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 	log.Printf("[DEBUG] KubernetesClusterConfig: %+v\n", config)
 	_, err := apiClient.UpdateKubernetesCluster(d.Id(), config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to update kubernetes cluster: %s", err)
+		return utils.DiagError("[ERR] failed to update kubernetes cluster", err)
 	}
 
 	createStateConf := &resource.StateChangeConf{
@@ -514,12 +686,12 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("error waiting for cluster (%s) to be created: %s", d.Id(), err)
 	}
 
-	return resourceKubernetesClusterRead(ctx, d, m)
+	return append(diags, resourceKubernetesClusterRead(ctx, d, m)...)
 }
 
 // function to delete the kubernetes cluster
 func resourceKubernetesClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -532,6 +704,209 @@ func resourceKubernetesClusterDelete(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("[INFO] an error occurred while tring to delete the kubernetes cluster %s", err)
 	}
 
+	firewallCreated := d.Get("firewall_created").(bool)
+	cleanup := d.Get("cleanup_on_delete").(bool)
+
+	// the firewall created for the cluster and the resources left by the
+	// cluster can only be deleted when the cluster is gone
+	if firewallCreated || cleanup {
+		deleteStateConf := &resource.StateChangeConf{
+			Pending: []string{"DELETING"},
+			Target:  []string{"DELETED"},
+			Refresh: func() (interface{}, string, error) {
+				resp, err := apiClient.GetKubernetesCluster(d.Id())
+				if err != nil {
+					if utils.IsNotFoundError(err) {
+						return 0, "DELETED", nil
+					}
+					return 0, "", err
+				}
+				return resp, "DELETING", nil
+			},
+			Timeout:    d.Timeout(schema.TimeoutDelete),
+			Delay:      3 * time.Second,
+			MinTimeout: 3 * time.Second,
+		}
+		if _, err := deleteStateConf.WaitForStateContext(ctx); err != nil {
+			return diag.Errorf("error waiting for cluster (%s) to be deleted: %s", d.Id(), err)
+		}
+	}
+
+	// the load balancers can use the firewall of the cluster, so they go first
+	if cleanup {
+		if err := cleanupKubernetesClusterResources(ctx, apiClient, d.Id()); err != nil {
+			return diag.Errorf("[ERR] failed to clean up the resources of the kubernetes cluster %s: %s", d.Id(), err)
+		}
+	}
+
+	if firewallCreated {
+		firewallID := d.Get("firewall_id").(string)
+		log.Printf("[INFO] deleting the firewall %s created for the kubernetes cluster %s", firewallID, d.Id())
+		if _, err := apiClient.DeleteFirewall(firewallID); err != nil {
+			return utils.DiagError("[ERR] failed to delete the firewall of the kubernetes cluster", err)
+		}
+	} else if d.Get("allow_api_access_from").(*schema.Set).Len() > 0 {
+		// the firewall is not deleted with the cluster, so we remove the rule we added
+		if err := syncKubernetesAPIAccessRule(apiClient, d.Get("firewall_id").(string), d.Id(), nil); err != nil {
+			return diag.Errorf("[ERR] failed to remove the API access rule of the kubernetes cluster: %s", err)
+		}
+	}
+
+	if err := removeKubeconfigFile(d.Get("kubeconfig_path").(string)); err != nil {
+		return diag.Errorf("[ERR] failed to remove the kubeconfig file of the kubernetes cluster: %s", err)
+	}
+
+	return nil
+}
+
+// deleteCreatedKubernetesFirewall delete the firewall created for the cluster
+// when the create fails before the cluster exists, nothing else would delete it
+func deleteCreatedKubernetesFirewall(d *schema.ResourceData, apiClient *civogo.Client, firewallID string) {
+	if !d.Get("firewall_created").(bool) {
+		return
+	}
+
+	log.Printf("[INFO] deleting the firewall %s created for the kubernetes cluster that failed to be created", firewallID)
+	if _, err := apiClient.DeleteFirewall(firewallID); err != nil {
+		log.Printf("[WARN] unable to delete the firewall %s created for the kubernetes cluster, delete it manually: %s", firewallID, err)
+	}
+}
+
+// cleanupKubernetesClusterResources delete the load balancers and the volumes
+// of a deleted cluster, the ones the controllers of the cluster created and
+// the API doesn't delete with the cluster. A volume still attached is waited
+// until the API detach it, the nodes of the cluster are already gone
+func cleanupKubernetesClusterResources(ctx context.Context, apiClient *civogo.Client, clusterID string) error {
+	loadBalancers, err := apiClient.ListLoadBalancers()
+	if err != nil {
+		return fmt.Errorf("failed to list the load balancers: %s", err)
+	}
+
+	for _, loadBalancer := range loadBalancers {
+		if loadBalancer.ClusterID != clusterID {
+			continue
+		}
+
+		log.Printf("[INFO] deleting the load balancer %s of the kubernetes cluster %s", loadBalancer.Name, clusterID)
+		if _, err := apiClient.DeleteLoadBalancer(loadBalancer.ID); err != nil && !utils.IsNotFoundError(err) {
+			return fmt.Errorf("failed to delete the load balancer %s: %s", loadBalancer.Name, err)
+		}
+	}
+
+	volumes, err := apiClient.ListVolumes()
+	if err != nil {
+		return fmt.Errorf("failed to list the volumes: %s", err)
+	}
+
+	for _, volume := range volumes {
+		if volume.ClusterID != clusterID {
+			continue
+		}
+
+		if volume.InstanceID != "" {
+			log.Printf("[INFO] waiting for the volume %s of the kubernetes cluster %s to be detached", volume.Name, clusterID)
+			if err := waitForVolumeDetach(ctx, apiClient, volume.ID, kubernetesClusterVolumeDetachTimeout); err != nil {
+				return fmt.Errorf("the volume %s was not detached: %s", volume.Name, err)
+			}
+		}
+
+		log.Printf("[INFO] deleting the volume %s of the kubernetes cluster %s", volume.Name, clusterID)
+		if _, err := apiClient.DeleteVolume(volume.ID); err != nil && !utils.IsNotFoundError(err) {
+			return fmt.Errorf("failed to delete the volume %s: %s", volume.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// writeKubeconfigFile write the kubeconfig to the file, only the owner can read it
+func writeKubeconfigFile(path string, kubeconfig string) error {
+	if err := ioutil.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		return err
+	}
+
+	// WriteFile doesn't change the permissions of a file that already exists
+	return os.Chmod(path, 0600)
+}
+
+// removeKubeconfigFile remove the kubeconfig file, if it was already removed there is nothing to do
+func removeKubeconfigFile(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	log.Printf("[INFO] removing the kubeconfig file %s", path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// custom diff for the kubernetes cluster, we check the firewall and the size
+// of the nodes exist in the region of the cluster at plan time, so we don't
+// fail in the middle of the apply
+func customizeDiffKubernetesCluster(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := checkKubernetesClusterSizes(d, m); err != nil {
+		return err
+	}
+
+	return checkKubernetesClusterFirewall(d, m)
+}
+
+// checkKubernetesClusterSizes check the size of the nodes of the cluster is valid
+func checkKubernetesClusterSizes(d *schema.ResourceDiff, m interface{}) error {
+	sizes := []string{}
+	if d.HasChange("target_nodes_size") && d.NewValueKnown("target_nodes_size") {
+		if size := d.Get("target_nodes_size").(string); size != "" {
+			sizes = append(sizes, size)
+		}
+	}
+	if d.HasChange("pools") {
+		for _, pool := range d.Get("pools").([]interface{}) {
+			if size, ok := pool.(map[string]interface{})["size"].(string); ok && size != "" {
+				sizes = append(sizes, size)
+			}
+		}
+	}
+
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	region, _ := d.Get("region").(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+
+	for _, size := range sizes {
+		if err := validateKubernetesSize(apiClient, size); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkKubernetesClusterFirewall check the firewall exist in the region of the cluster
+func checkKubernetesClusterFirewall(d *schema.ResourceDiff, m interface{}) error {
+	firewallID, ok := d.GetOk("firewall_id")
+	if !ok || !d.NewValueKnown("firewall_id") || (d.Id() != "" && !d.HasChange("firewall_id")) {
+		return nil
+	}
+
+	// use a client for the region of the cluster, the diff can't change the shared client
+	region, _ := d.GetOk("region")
+	regionValue, _ := region.(string)
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), regionValue)
+
+	firewall, err := apiClient.FindFirewall(firewallID.(string))
+	if err != nil {
+		return fmt.Errorf("[ERR] the firewall %s was not found in the region %s of the cluster: %s", firewallID.(string), apiClient.Region, err)
+	}
+
+	if networkID, ok := d.GetOk("network_id"); ok && d.NewValueKnown("network_id") && firewall.NetworkID != networkID.(string) {
+		return fmt.Errorf("[ERR] the firewall %s is not part of the network %s of the cluster", firewall.ID, networkID.(string))
+	}
+
 	return nil
 }
 
@@ -557,8 +932,36 @@ func flattenInstances(instances []civogo.KubernetesInstance) []interface{} {
 	return flattenedInstances
 }
 
-// function to flatten all instances inside the cluster
-func flattenNodePool(cluster *civogo.KubernetesCluster, d *schema.ResourceData) []interface{} {
+// kubernetesNodesPrivateIP returns the private IP of the nodes of the cluster
+// by instance ID, the cluster doesn't have them so they are taken from the
+// instances of the cluster. The IPs are only informative, so an error is logged
+// and the IPs are left empty
+func kubernetesNodesPrivateIP(apiClient *civogo.Client, cluster *civogo.KubernetesCluster) map[string]string {
+	nodes := 0
+	for _, pool := range cluster.Pools {
+		nodes += len(pool.Instances)
+	}
+	if nodes == 0 {
+		return nil
+	}
+
+	instances, err := apiClient.ListKubernetesClusterInstances(cluster.ID)
+	if err != nil {
+		log.Printf("[WARN] unable to read the private IPs of the nodes of the kubernetes cluster %s: %s", cluster.ID, err)
+		return nil
+	}
+
+	privateIPs := make(map[string]string, nodes)
+	for _, instance := range instances {
+		privateIPs[instance.ID] = instance.PrivateIP
+	}
+
+	return privateIPs
+}
+
+// function to flatten all instances inside the cluster, privateIPs has the
+// private IP of the nodes by instance ID
+func flattenNodePool(cluster *civogo.KubernetesCluster, d *schema.ResourceData, privateIPs map[string]string) []interface{} {
 
 	if cluster.Pools == nil {
 		return nil
@@ -573,13 +976,16 @@ func flattenNodePool(cluster *civogo.KubernetesCluster, d *schema.ResourceData)
 			for _, v := range pool.Instances {
 
 				rawPoolInstance := map[string]interface{}{
-					"hostname":  v.Hostname,
-					"size":      pool.Size,
-					"cpu_cores": v.CPUCores,
-					"ram_mb":    v.RAMMegabytes,
-					"disk_gb":   v.DiskGigabytes,
-					"status":    v.Status,
-					"tags":      v.Tags,
+					"instance_id": v.ID,
+					"hostname":    v.Hostname,
+					"size":        pool.Size,
+					"cpu_cores":   v.CPUCores,
+					"ram_mb":      v.RAMMegabytes,
+					"disk_gb":     v.DiskGigabytes,
+					"status":      v.Status,
+					"tags":        v.Tags,
+					"public_ip":   v.PublicIP,
+					"private_ip":  privateIPs[v.ID],
 				}
 				flattenedPoolInstance = append(flattenedPoolInstance, rawPoolInstance)
 			}
@@ -635,3 +1041,160 @@ func expandNodePools(nodePools []interface{}) []civogo.KubernetesClusterPoolConf
 
 	return expandedNodePools
 }
+
+// kubernetesSizesCache keep the sizes of every region, so the plan only list
+// the sizes once per region even with many clusters and node pools
+var kubernetesSizesCache sync.Map
+
+type kubernetesSizesCacheKey struct {
+	client *civogo.Client
+	region string
+}
+
+// listSizes returns the sizes of the region of the client, using the cache
+func listSizes(apiClient *civogo.Client) ([]civogo.InstanceSize, error) {
+	key := kubernetesSizesCacheKey{client: apiClient, region: utils.NormalizeRegion(apiClient.Region)}
+	if sizes, ok := kubernetesSizesCache.Load(key); ok {
+		return sizes.([]civogo.InstanceSize), nil
+	}
+
+	sizes, err := apiClient.ListInstanceSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetesSizesCache.Store(key, sizes)
+	return sizes, nil
+}
+
+// validateKubernetesSize check the size exist in the region of the client,
+// the error has the list of the kubernetes sizes that can be used
+func validateKubernetesSize(apiClient *civogo.Client, size string) error {
+	sizes, err := listSizes(apiClient)
+	if err != nil {
+		return fmt.Errorf("[ERR] failed to list the sizes to check the size %s: %s", size, err)
+	}
+
+	valid := []string{}
+	for _, s := range sizes {
+		if s.Name == size {
+			return nil
+		}
+		if s.Selectable && strings.Contains(s.Name, ".kube.") {
+			valid = append(valid, s.Name)
+		}
+	}
+	sort.Strings(valid)
+
+	return fmt.Errorf("[ERR] the size %s is not available in the region %s, the valid sizes are: %s", size, utils.NormalizeRegion(apiClient.Region), strings.Join(valid, ", "))
+}
+
+// kubernetesAPIAccessLabelPrefix is the start of the label of the firewall rule
+// managed by allow_api_access_from, the ID of the cluster follows it
+const kubernetesAPIAccessLabelPrefix = "kubernetes-api-access"
+
+// kubernetesAPIAccessLabel returns the label of the API access rule of the
+// cluster, it has the ID of the cluster so the clusters that share a firewall
+// never change the rules of each other
+func kubernetesAPIAccessLabel(clusterID string) string {
+	return fmt.Sprintf("%s-%s", kubernetesAPIAccessLabelPrefix, clusterID)
+}
+
+// kubernetesAPIPort is the port of the Kubernetes API server
+const kubernetesAPIPort = "6443"
+
+// kubernetesClusterVolumeDetachTimeout is how long cleanup_on_delete waits for
+// a volume of a deleted cluster to be detached
+const kubernetesClusterVolumeDetachTimeout = 10 * time.Minute
+
+// syncKubernetesAPIAccessRule replace the API access rule of the cluster in the
+// firewall with one for the cidrs, the rules can't be updated so we delete and
+// create them. With no cidrs the rule is only removed
+func syncKubernetesAPIAccessRule(apiClient *civogo.Client, firewallID string, clusterID string, cidrs []string) error {
+	if firewallID == "" || clusterID == "" {
+		return nil
+	}
+
+	firewallRuleMutexKV.Lock(firewallID)
+	defer firewallRuleMutexKV.Unlock(firewallID)
+
+	rules, err := apiClient.ListFirewallRules(firewallID)
+	if err != nil {
+		if len(cidrs) == 0 && utils.IsNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list the rules of the firewall %s: %s", firewallID, err)
+	}
+
+	label := kubernetesAPIAccessLabel(clusterID)
+	cidrs = utils.SortedCidr(cidrs)
+	kept := false
+	for _, rule := range rules {
+		if rule.Label != label {
+			continue
+		}
+
+		// the rule is already the expected one, any other copy is removed
+		if !kept && len(cidrs) > 0 && strings.Join(utils.SortedCidr(rule.Cidr), ",") == strings.Join(cidrs, ",") {
+			kept = true
+			continue
+		}
+
+		log.Printf("[INFO] deleting the API access rule %s from the firewall %s", rule.ID, firewallID)
+		if _, err := apiClient.DeleteFirewallRule(firewallID, rule.ID); err != nil {
+			return fmt.Errorf("failed to delete the rule %s of the firewall %s: %s", rule.ID, firewallID, err)
+		}
+	}
+
+	if len(cidrs) == 0 || kept {
+		return nil
+	}
+
+	log.Printf("[INFO] allowing the access to the Kubernetes API from %v in the firewall %s", cidrs, firewallID)
+	_, err = apiClient.NewFirewallRule(&civogo.FirewallRuleConfig{
+		FirewallID: firewallID,
+		Protocol:   "tcp",
+		StartPort:  kubernetesAPIPort,
+		EndPort:    kubernetesAPIPort,
+		Direction:  "ingress",
+		Action:     "allow",
+		Cidr:       cidrs,
+		Label:      label,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the rule in the firewall %s: %s", firewallID, err)
+	}
+
+	return nil
+}
+
+// expandStringSet returns the strings of the set
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, 0, set.Len())
+	for _, value := range set.List() {
+		values = append(values, value.(string))
+	}
+	return values
+}
+
+// readKubernetesAPIAccessRule returns the cidrs of the API access rules of the cluster in the firewall
+func readKubernetesAPIAccessRule(apiClient *civogo.Client, firewallID string, clusterID string) ([]string, error) {
+	if firewallID == "" {
+		return nil, nil
+	}
+
+	rules, err := apiClient.ListFirewallRules(firewallID)
+	if err != nil {
+		return nil, err
+	}
+
+	label := kubernetesAPIAccessLabel(clusterID)
+	var cidrs []string
+	for _, rule := range rules {
+		if rule.Label == label {
+			cidrs = append(cidrs, rule.Cidr...)
+		}
+	}
+
+	return utils.SortedCidr(cidrs), nil
+}
@@ -28,10 +28,11 @@ func resourceKubernetesClusterNodePool() *schema.Resource {
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
 			"region": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "The region of the node pool, has to match that of the cluster",
-				ValidateFunc: validation.StringIsNotEmpty,
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The region of the node pool, has to match that of the cluster",
+				ValidateFunc:     validation.StringIsNotEmpty,
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 			"num_target_nodes": {
 				Type:        schema.TypeInt,
@@ -55,7 +56,7 @@ func resourceKubernetesClusterNodePool() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "the size of each node (optional, the default is currently g4s.kube.medium)",
+				Description: "the size of each node (optional, the default is currently g4s.kube.medium), it is checked at plan time against the sizes available in the region",
 			},
 		},
 		CreateContext: resourceKubernetesClusterNodePoolCreate,
@@ -70,12 +71,32 @@ func resourceKubernetesClusterNodePool() *schema.Resource {
 			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
+		CustomizeDiff: customizeDiffKubernetesClusterNodePool,
 	}
 }
 
+// custom diff for the node pool, we check the size of the nodes exist in the
+// region at plan time, so we don't fail in the middle of the apply
+func customizeDiffKubernetesClusterNodePool(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	for _, key := range []string{"size", "target_nodes_size"} {
+		if !d.HasChange(key) || !d.NewValueKnown(key) {
+			continue
+		}
+		if size := d.Get(key).(string); size != "" {
+			if err := validateKubernetesSize(apiClient, size); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // function to create a new cluster
 func resourceKubernetesClusterNodePoolCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -123,14 +144,14 @@ func resourceKubernetesClusterNodePoolCreate(ctx context.Context, d *schema.Reso
 	log.Printf("[INFO] Creating a new kubernetes cluster pool %s", poolID[:6])
 	_, err = apiClient.UpdateKubernetesCluster(getKubernetesCluster.ID, config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create the kubernetes cluster: %s", err)
+		return utils.DiagError("[ERR] failed to create the kubernetes cluster", err)
 	}
 
 	d.SetId(poolID)
 
 	err = waitForKubernetesNodePoolCreate(apiClient, timeout, getKubernetesCluster.ID, poolID)
 	if err != nil {
-		return diag.Errorf("Error creating Kubernetes node pool: %s", err)
+		return utils.DiagError("Error creating Kubernetes node pool", err)
 	}
 
 	return resourceKubernetesClusterNodePoolRead(ctx, d, m)
@@ -139,7 +160,7 @@ func resourceKubernetesClusterNodePoolCreate(ctx context.Context, d *schema.Reso
 
 // function to read the kubernetes cluster
 func resourceKubernetesClusterNodePoolRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 	clusterID := d.Get("cluster_id").(string)
 
 	log.Printf("[INFO] retrieving the kubernetes cluster %s", clusterID)
@@ -149,7 +170,7 @@ func resourceKubernetesClusterNodePoolRead(ctx context.Context, d *schema.Resour
 			d.SetId("")
 			return nil
 		}
-		return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
+		return utils.DiagError("[ERR] failed to find the kubernetes cluster", err)
 	}
 
 	d.Set("cluster_id", resp.ID)
@@ -165,7 +186,7 @@ func resourceKubernetesClusterNodePoolRead(ctx context.Context, d *schema.Resour
 
 // function to update the kubernetes cluster
 func resourceKubernetesClusterNodePoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -207,12 +228,12 @@ func resourceKubernetesClusterNodePoolUpdate(ctx context.Context, d *schema.Reso
 	log.Printf("[INFO] updating the kubernetes cluster %s", d.Id())
 	_, err = apiClient.UpdateKubernetesCluster(getKubernetesCluster.ID, config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to update kubernetes cluster: %s", err)
+		return utils.DiagError("[ERR] failed to update kubernetes cluster", err)
 	}
 
 	err = waitForKubernetesNodePoolCreate(apiClient, timeout, getKubernetesCluster.ID, d.Id())
 	if err != nil {
-		return diag.Errorf("Error creating Kubernetes node pool: %s", err)
+		return utils.DiagError("Error creating Kubernetes node pool", err)
 	}
 
 	return resourceKubernetesClusterNodePoolRead(ctx, d, m)
@@ -220,7 +241,7 @@ func resourceKubernetesClusterNodePoolUpdate(ctx context.Context, d *schema.Reso
 
 // function to delete the kubernetes cluster
 func resourceKubernetesClusterNodePoolDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	clusterID := d.Get("cluster_id").(string)
 	getKubernetesCluster, err := apiClient.GetKubernetesCluster(clusterID)
@@ -260,7 +281,7 @@ func resourceKubernetesClusterNodePoolDelete(ctx context.Context, d *schema.Reso
 
 // custom import to able to add a node pool to the terraform
 func resourceKubernetesClusterNodePoolImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 	regions, err := apiClient.ListRegions()
 	if err != nil {
 		return nil, err
@@ -290,7 +311,7 @@ func resourceKubernetesClusterNodePoolImport(d *schema.ResourceData, m interface
 				poolFound = true
 				d.SetId(v.ID)
 				d.Set("cluster_id", resp.ID)
-				d.Set("region", currentRegionCode)
+				d.Set("region", utils.NormalizeRegion(currentRegionCode))
 				d.Set("num_target_nodes", v.Count)
 				d.Set("target_nodes_size", v.Size)
 			}
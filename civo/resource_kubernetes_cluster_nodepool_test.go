@@ -72,7 +72,7 @@ func testAccCheckCivoKubernetesClusterNodePoolResourceExists(n string, kubernete
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.GetKubernetesCluster(kubernetes.ID)
 		if err != nil {
 			return fmt.Errorf("Kuberenetes Cluster not found: (%s) %s", rs.Primary.ID, err)
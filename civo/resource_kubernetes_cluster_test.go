@@ -1,7 +1,16 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/civo/civogo"
@@ -46,12 +55,239 @@ func TestAccCivoKubernetesCluster_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resName, "dns_entry"),
 					resource.TestCheckResourceAttrSet(resName, "built_at"),
 					resource.TestCheckResourceAttrSet(resName, "created_at"),
+					resource.TestCheckResourceAttrSet(resName, "firewall_id"),
+					resource.TestCheckResourceAttr(resName, "firewall_created", "true"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccCivoKubernetesClusterFirewall_notFound(t *testing.T) {
+	// generate a random name for each test run
+	var kubernetesClusterName = acctest.RandomWithPrefix("tf-test") + ".example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoKubernetesClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckCivoKubernetesClusterConfigFirewall(kubernetesClusterName, "00000000-0000-0000-0000-000000000000"),
+				ExpectError: regexp.MustCompile("was not found in the region"),
+			},
+		},
+	})
+}
+
+func TestValidateKubernetesSize(t *testing.T) {
+	var lists int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&lists, 1)
+		fmt.Fprint(rw, `[
+			{"name": "g4s.kube.medium", "selectable": true},
+			{"name": "g4s.kube.large", "selectable": true},
+			{"name": "g3.small", "selectable": true}
+		]`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := validateKubernetesSize(client, "g4s.kube.medium"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = validateKubernetesSize(client, "g3.medium")
+	if err == nil {
+		t.Fatal("expected an error for a size that doesn't exist")
+	}
+	if !strings.Contains(err.Error(), "g4s.kube.large, g4s.kube.medium") {
+		t.Fatalf("expected the valid sizes in the error, got: %s", err)
+	}
+
+	if atomic.LoadInt32(&lists) != 1 {
+		t.Fatalf("expected the sizes to be listed once, they were listed %d times", lists)
+	}
+}
+
+func TestSyncKubernetesAPIAccessRule(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		switch req.Method {
+		case http.MethodGet:
+			fmt.Fprint(rw, `[
+				{"id": "ssh", "label": "ssh", "protocol": "tcp", "start_port": "22", "cidr": ["0.0.0.0/0"]},
+				{"id": "api", "label": "kubernetes-api-access-cluster", "protocol": "tcp", "start_port": "6443", "cidr": ["10.0.0.0/8", "1.2.3.4/32"]},
+				{"id": "other", "label": "kubernetes-api-access-other-cluster", "protocol": "tcp", "start_port": "6443", "cidr": ["9.9.9.9/32"]},
+				{"id": "legacy", "label": "kubernetes-api-access", "protocol": "tcp", "start_port": "6443", "cidr": ["8.8.8.8/32"]}
+			]`)
+		case http.MethodPost:
+			fmt.Fprint(rw, `{"id": "new"}`)
+		default:
+			fmt.Fprint(rw, `{"result": "success"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// the rule already allows the cidrs, in another order, the rules of the
+	// other cluster sharing the firewall and the old label are never touched
+	if err := syncKubernetesAPIAccessRule(client, "fw", "cluster", []string{"1.2.3.4/32", "10.0.0.0/8"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected the rule to be kept, got the requests %v", requests)
+	}
+
+	requests = nil
+	if err := syncKubernetesAPIAccessRule(client, "fw", "cluster", []string{"5.6.7.8/32"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "GET /v2/firewalls/fw/rules, DELETE /v2/firewalls/fw/rules/api, POST /v2/firewalls/fw/rules"
+	if strings.Join(requests, ", ") != expected {
+		t.Fatalf("expected the requests %s, got %v", expected, requests)
+	}
+
+	requests = nil
+	if err := syncKubernetesAPIAccessRule(client, "fw", "cluster", nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected = "GET /v2/firewalls/fw/rules, DELETE /v2/firewalls/fw/rules/api"
+	if strings.Join(requests, ", ") != expected {
+		t.Fatalf("expected the requests %s, got %v", expected, requests)
+	}
+
+	cidrs, err := readKubernetesAPIAccessRule(client, "fw", "cluster")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Join(cidrs, ",") != "1.2.3.4/32,10.0.0.0/8" {
+		t.Fatalf("expected the cidrs of the API access rule, got %v", cidrs)
+	}
+}
+
+func TestResourceKubernetesClusterCreate_deleteCreatedFirewall(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/v2/firewalls":
+			fmt.Fprint(rw, `{"id": "fw-1", "name": "k8s-test", "result": "success"}`)
+		case req.Method == http.MethodGet && req.URL.Path == "/v2/firewalls/fw-1/rules":
+			fmt.Fprint(rw, `[]`)
+		case req.Method == http.MethodPost && req.URL.Path == "/v2/kubernetes/clusters":
+			rw.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(rw, `{"code": "internal_error", "reason": "failed"}`)
+		default:
+			fmt.Fprint(rw, `{"result": "success"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceKubernetesCluster().TestResourceData()
+	d.Set("name", "test")
+	d.Set("network_id", "net-1")
+
+	if diags := resourceKubernetesClusterCreate(context.Background(), d, &providerMeta{client: client}); !diags.HasError() {
+		t.Fatal("expected an error creating the cluster")
+	}
+	if requests[len(requests)-1] != "DELETE /v2/firewalls/fw-1" {
+		t.Fatalf("expected the firewall created for the cluster to be deleted, got the requests %v", requests)
+	}
+}
+
+func TestCleanupKubernetesClusterResources(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodDelete:
+			deleted = append(deleted, req.URL.Path)
+			fmt.Fprint(rw, `{"result": "success"}`)
+		case req.URL.Path == "/v2/loadbalancers":
+			fmt.Fprint(rw, `[
+				{"id": "lb-1", "name": "ingress", "cluster_id": "cluster"},
+				{"id": "lb-2", "name": "other", "cluster_id": "other-cluster"},
+				{"id": "lb-3", "name": "standalone"}
+			]`)
+		case req.URL.Path == "/v2/volumes":
+			fmt.Fprint(rw, `[
+				{"id": "vol-1", "name": "pvc-1", "cluster_id": "cluster"},
+				{"id": "vol-2", "name": "data", "instance_id": "instance"}
+			]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := cleanupKubernetesClusterResources(context.Background(), client, "cluster"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"/v2/loadbalancers/lb-1", "/v2/volumes/vol-1"}
+	if strings.Join(deleted, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected only the resources of the cluster to be deleted, got %v", deleted)
+	}
+}
+
+func TestWriteKubeconfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := ioutil.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := writeKubeconfigFile(path, "apiVersion: v1"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(content) != "apiVersion: v1" {
+		t.Fatalf("unexpected kubeconfig: %s", content)
+	}
+
+	if err := removeKubeconfigFile(path); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("expected the kubeconfig file to be removed")
+	}
+
+	// removing a file that is already gone is not an error
+	if err := removeKubeconfigFile(path); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func TestAccCivoKubernetesClusterSize_update(t *testing.T) {
 	var kubernetes civogo.KubernetesCluster
 
@@ -239,7 +475,7 @@ func testAccCheckCivoKubernetesClusterResourceExists(n string, kubernetes *civog
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.GetKubernetesCluster(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Kuberenetes Cluster not found: (%s) %s", rs.Primary.ID, err)
@@ -254,7 +490,7 @@ func testAccCheckCivoKubernetesClusterResourceExists(n string, kubernetes *civog
 }
 
 func testAccCheckCivoKubernetesClusterDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_kubernetes_cluster" {
@@ -304,3 +540,55 @@ resource "civo_kubernetes_cluster" "foobar" {
 	cni = "calico"
 }`, name)
 }
+
+func testAccCheckCivoKubernetesClusterConfigFirewall(name string, firewallID string) string {
+	return fmt.Sprintf(`
+resource "civo_kubernetes_cluster" "foobar" {
+	name = "%s"
+	num_target_nodes = 2
+	firewall_id = "%s"
+}`, name, firewallID)
+}
+
+func TestFlattenNodePoolInstances(t *testing.T) {
+	client, server, err := civogo.NewClientForTesting(map[string]string{
+		"/v2/kubernetes/clusters/cluster/instances": `[
+			{"id": "node-1", "hostname": "k3s-node-1", "private_ip": "192.168.1.2", "public_ip": "1.2.3.4"}
+		]`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer server.Close()
+
+	cluster := &civogo.KubernetesCluster{
+		ID: "cluster",
+		Pools: []civogo.KubernetesPool{{
+			ID:   "pool",
+			Size: "g4s.kube.small",
+			Instances: []civogo.KubernetesInstance{
+				{ID: "node-1", Hostname: "k3s-node-1", PublicIP: "1.2.3.4"},
+				{ID: "node-2", Hostname: "k3s-node-2", PublicIP: "5.6.7.8"},
+			},
+		}},
+	}
+
+	d := resourceKubernetesCluster().TestResourceData()
+	d.Set("pools", []interface{}{map[string]interface{}{"id": "pool", "size": "g4s.kube.small", "node_count": 2}})
+
+	if err := d.Set("pools", flattenNodePool(cluster, d, kubernetesNodesPrivateIP(client, cluster))); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []map[string]string{
+		{"instance_id": "node-1", "hostname": "k3s-node-1", "public_ip": "1.2.3.4", "private_ip": "192.168.1.2"},
+		{"instance_id": "node-2", "hostname": "k3s-node-2", "public_ip": "5.6.7.8", "private_ip": ""},
+	}
+	for i, instance := range expected {
+		for key, value := range instance {
+			if got := d.Get(fmt.Sprintf("pools.0.instances.%d.%s", i, key)).(string); got != value {
+				t.Fatalf("expected %s of the node %d to be %q, got %q", key, i, value, got)
+			}
+		}
+	}
+}
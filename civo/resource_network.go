@@ -22,9 +22,11 @@ func resourceNetwork() *schema.Resource {
 				ValidateFunc: utils.ValidateName,
 			},
 			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The region of the network",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The region of the network",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 			// Computed resource
 			"name": {
@@ -50,7 +52,7 @@ func resourceNetwork() *schema.Resource {
 
 // function to create a new network
 func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -60,7 +62,7 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 	log.Printf("[INFO] creating the new network %s", d.Get("label").(string))
 	network, err := apiClient.NewNetwork(d.Get("label").(string))
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new network: %s", err)
+		return utils.DiagError("[ERR] failed to create a new network", err)
 	}
 
 	d.SetId(network.ID)
@@ -70,7 +72,7 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 // function to read a network
 func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -87,7 +89,7 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 			return nil
 		}
 
-		return diag.Errorf("[ERR] failed to list the network: %s", err)
+		return utils.DiagError("[ERR] failed to list the network", err)
 	}
 
 	for _, net := range resp {
@@ -97,7 +99,7 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 
 	d.Set("name", CurrentNetwork.Name)
-	d.Set("region", apiClient.Region)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
 	d.Set("label", CurrentNetwork.Label)
 	d.Set("default", CurrentNetwork.Default)
 	return nil
@@ -105,7 +107,7 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 // function to update the network
 func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -125,7 +127,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 // function to delete a network
 func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -92,7 +92,7 @@ func testAccCheckCivoNetworkResourceExists(n string, network *civogo.Network) re
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindNetwork(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Network not found: (%s) %s", rs.Primary.ID, err)
@@ -116,7 +116,7 @@ func testAccCheckCivoNetworkUpdated(network *civogo.Network, name string) resour
 }
 
 func testAccCheckCivoNetworkDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_network" {
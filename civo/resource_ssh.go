@@ -4,7 +4,6 @@ import (
 	"context"
 	"log"
 
-	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -46,12 +45,12 @@ func resourceSSHKey() *schema.Resource {
 
 // function to create a new ssh key
 func resourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] creating the new ssh key %s", d.Get("name").(string))
 	sshKey, err := apiClient.NewSSHKey(d.Get("name").(string), d.Get("public_key").(string))
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new ssh key: %s", err)
+		return utils.DiagError("[ERR] failed to create a new ssh key", err)
 	}
 
 	d.SetId(sshKey.ID)
@@ -61,7 +60,7 @@ func resourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 // function to read a ssh key
 func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] retrieving the new ssh key %s", d.Get("name").(string))
 	sshKey, err := apiClient.FindSSHKey(d.Id())
@@ -71,7 +70,7 @@ func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface
 			return nil
 		}
 
-		return diag.Errorf("[ERR] error retrieving ssh key: %s", err)
+		return utils.DiagError("[ERR] error retrieving ssh key", err)
 	}
 
 	d.Set("name", sshKey.Name)
@@ -82,7 +81,7 @@ func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface
 
 // function to update the ssh key
 func resourceSSHKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	if d.HasChange("name") {
 		if d.Get("name").(string) != "" {
@@ -99,7 +98,7 @@ func resourceSSHKeyUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 
 // function to delete the ssh key
 func resourceSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] deleting the ssh key %s", d.Id())
 	_, err := apiClient.DeleteSSHKey(d.Id())
@@ -104,7 +104,7 @@ func testAccCheckCivoSSHKeyResourceExists(n string, SSHKey *civogo.SSHKey) resou
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindSSHKey(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Ssh key not found: (%s) %s", rs.Primary.ID, err)
@@ -128,7 +128,7 @@ func testAccCheckCivoSSHKeyUpdated(SSHKey *civogo.SSHKey, name string) resource.
 }
 
 func testAccCheckCivoSSHKeyDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_ssh_key" {
@@ -4,17 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Volume resource, with this we can create and manage all volume
 func resourceVolume() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Civo volume which can be attached to an instance in order to provide expanded storage.",
+		Description: "Provides a Civo volume which can be attached to an instance in order to provide expanded storage. The volume is detached from its instance before it is deleted, unless `deletion_protection` is enabled.",
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -28,15 +30,30 @@ func resourceVolume() *schema.Resource {
 				Description: "A minimum of 1 and a maximum of your available disk space from your quota specifies the size of the volume in gigabytes ",
 			},
 			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The region for the volume, if not declare we use the region in declared in the provider.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The region for the volume, if not declare we use the region in declared in the provider.",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
 			},
 			"network_id": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The network that the volume belongs to",
 			},
+			"bootable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "If the volume can be used to boot an instance, the backend can't change it in an existing volume so changing it recreates the volume",
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If enabled, the volume can't be deleted, disable it and apply before destroying the volume",
+			},
 			// Computed resource
 			"mount_point": {
 				Type:        schema.TypeString,
@@ -51,18 +68,23 @@ func resourceVolume() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceVolumeImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 	}
 }
 
 // function to create the new volume
 func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	log.Printf("[INFO] configuring the volume %s", d.Get("name").(string))
 	config := &civogo.VolumeConfig{
 		Name:          d.Get("name").(string),
 		SizeGigabytes: d.Get("size_gb").(int),
 		NetworkID:     d.Get("network_id").(string),
+		Bootable:      d.Get("bootable").(bool),
 
 		// if "region" is set at provider level, use it
 		Region: apiClient.Region,
@@ -82,17 +104,49 @@ func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	volume, err := apiClient.NewVolume(config)
 	if err != nil {
-		return diag.Errorf("[ERR] failed to create a new volume: %s", err)
+		return utils.DiagError("[ERR] failed to create a new volume", err)
 	}
 
 	d.SetId(volume.ID)
 
+	// the new volume is not always listed right away, and the attachments fail to find it
+	if _, err := waitForVolumeListed(ctx, apiClient, d.Id()); err != nil {
+		return diag.Errorf("error waiting for volume (%s) to be listed: %s", d.Id(), err)
+	}
+
 	return resourceVolumeRead(ctx, d, m)
 }
 
+// volumeListedTimeout is how long we wait for a new volume to be listed, it
+// doesn't depend on the create timeout so a volume that will never be listed
+// fails quickly
+const volumeListedTimeout = 1 * time.Minute
+
+// waitForVolumeListed wait until the volume we just created can be found, the
+// API can take some seconds to list a new volume. Don't use it for the volumes
+// set by the user, a wrong ID would only fail after the timeout
+func waitForVolumeListed(ctx context.Context, apiClient *civogo.Client, volumeID string) (*civogo.Volume, error) {
+	var volume *civogo.Volume
+	err := resource.RetryContext(ctx, volumeListedTimeout, func() *resource.RetryError {
+		resp, err := apiClient.FindVolume(volumeID)
+		if err != nil {
+			if utils.IsNotFoundError(err) {
+				log.Printf("[DEBUG] the volume %s is not listed yet", volumeID)
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+
+		volume = resp
+		return nil
+	})
+
+	return volume, err
+}
+
 // function to read the volume
 func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
@@ -106,13 +160,15 @@ func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface
 			d.SetId("")
 			return nil
 		}
-		return diag.Errorf("[ERR] failed retrieving the volume: %s", err)
+		return utils.DiagError("[ERR] failed retrieving the volume", err)
 	}
 
 	d.Set("name", resp.Name)
 	d.Set("network_id", resp.NetworkID)
 	d.Set("size_gb", resp.SizeGigabytes)
 	d.Set("mount_point", resp.MountPoint)
+	d.Set("bootable", resp.Bootable)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
 
 	return nil
 }
@@ -120,7 +176,7 @@ func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface
 // function to update the volume
 func resourceVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	// apiClient := m.(*civogo.Client)
+	// apiClient := m.(*providerMeta).Client()
 
 	// // overwrite the region if is define in the datasource
 	// if region, ok := d.GetOk("region"); ok {
@@ -179,17 +235,46 @@ func resourceVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	return resourceVolumeRead(ctx, d, m)
 }
 
-// function to delete the volume
+// function to delete the volume, an attached volume is detached first
+// because the API fails to delete a volume that is still attached
 func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("[ERR] the volume %s has deletion_protection enabled, set it to false and apply before deleting the volume", d.Id())
+	}
+
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if is define in the datasource
 	if region, ok := d.GetOk("region"); ok {
 		apiClient.Region = region.(string)
 	}
 
+	// the volume is detached before it is deleted, the cached volumes of the
+	// attachments must not keep it attached
+	defer invalidateAttachedVolumes(m, apiClient)
+
+	log.Printf("[INFO] retrieving the volume %s", d.Id())
+	resp, err := apiClient.FindVolume(d.Id())
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil
+		}
+		return utils.DiagError("[ERR] failed retrieving the volume", err)
+	}
+
+	if resp.InstanceID != "" {
+		log.Printf("[INFO] detaching the volume %s from the instance %s before deleting it", d.Id(), resp.InstanceID)
+		if _, err := apiClient.DetachVolume(d.Id()); err != nil {
+			return diag.Errorf("[ERR] an error occurred while tring to detach the volume %s", err)
+		}
+
+		if err := waitForVolumeDetach(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("[ERR] error waiting for volume (%s) to be detached: %s", d.Id(), err)
+		}
+	}
+
 	log.Printf("[INFO] deleting the volume %s", d.Id())
-	_, err := apiClient.DeleteVolume(d.Id())
+	_, err = apiClient.DeleteVolume(d.Id())
 	if err != nil {
 		return diag.Errorf("[ERR] an error occurred while tring to delete the volume %s", err)
 	}
@@ -198,7 +283,7 @@ func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 // custom import to able to import a volume
 func resourceVolumeImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 	regions, err := apiClient.ListRegions()
 	if err != nil {
 		return nil, err
@@ -224,9 +309,11 @@ func resourceVolumeImport(d *schema.ResourceData, m interface{}) ([]*schema.Reso
 				d.SetId(volume.ID)
 				d.Set("name", volume.Name)
 				d.Set("network_id", volume.NetworkID)
-				d.Set("region", currentRegion)
+				d.Set("region", utils.NormalizeRegion(currentRegion))
 				d.Set("size_gb", volume.SizeGigabytes)
 				d.Set("mount_point", volume.MountPoint)
+				d.Set("bootable", volume.Bootable)
+				d.Set("deletion_protection", false)
 			}
 		}
 	}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// volumeAttachmentMutexKV serialize the attachments to the same instance, so two
+// volumes are never attached to it at the same time. It doesn't choose the
+// order, the attachments that don't depend on each other can run in any order
+var volumeAttachmentMutexKV = utils.NewMutexKV()
+
 // Volume resource, with this we can create and manage all volume
 func resourceVolumeAttachment() *schema.Resource {
 	return &schema.Resource{
@@ -33,21 +41,45 @@ func resourceVolumeAttachment() *schema.Resource {
 				Description:  "The ID of target volume for attachment",
 			},
 			"region": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The region for the volume attachment",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region for the volume attachment",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
+			},
+			"force_detach": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "If the volume is not detached in half of the delete timeout, stop the instance, wait until it is stopped and detach the volume again in the other half. " +
+					"The Civo API has no force detach, so this is a stop + detach. The instance is started again after the second detach, even if it fails, unless it was already stopped. " +
+					"Use it only for unresponsive instances, as the filesystem of the volume can be corrupted",
+			},
+			"device_path": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The device path the volume got in the instance, as returned by the API. The Civo API doesn't let you choose the device, " +
+					"so mount the volume by the filesystem label or UUID to be sure it is the right one. It is empty if the API doesn't return it",
 			},
 		},
 		CreateContext: resourceVolumeAttachmentCreate,
 		ReadContext:   resourceVolumeAttachmentRead,
+		UpdateContext: resourceVolumeAttachmentUpdate,
 		DeleteContext: resourceVolumeAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVolumeAttachmentImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 	}
 }
 
 // function to create the new volume
 func resourceVolumeAttachmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -60,9 +92,12 @@ func resourceVolumeAttachmentCreate(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[INFO] retrieving the volume %s", volumeID)
 	volume, err := apiClient.FindVolume(volumeID)
 	if err != nil {
-		return diag.Errorf("[ERR] Error retrieving volume: %s", err)
+		return utils.DiagError("[ERR] Error retrieving volume", err)
 	}
 
+	volumeAttachmentMutexKV.Lock(instanceID)
+	defer volumeAttachmentMutexKV.Unlock(instanceID)
+
 	if volume.InstanceID == "" || volume.InstanceID != instanceID {
 		log.Printf("[INFO] attaching the volume %s to instance %s", volumeID, instanceID)
 		_, err := apiClient.AttachVolume(volumeID, instanceID)
@@ -71,14 +106,20 @@ func resourceVolumeAttachmentCreate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	d.SetId(resource.PrefixedUniqueId(fmt.Sprintf("%s-%s-", instanceID, volumeID)))
+	invalidateAttachedVolumes(m, apiClient)
+
+	id, err := volumeAttachmentID(apiClient, instanceID, volumeID)
+	if err != nil {
+		return utils.DiagError("[ERR] failed to retrive the default region", err)
+	}
+	d.SetId(id)
 
 	return resourceVolumeAttachmentRead(ctx, d, m)
 }
 
 // function to read the volume
 func resourceVolumeAttachmentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -89,27 +130,51 @@ func resourceVolumeAttachmentRead(ctx context.Context, d *schema.ResourceData, m
 	volumeID := d.Get("volume_id").(string)
 
 	log.Printf("[INFO] retrieving the volume %s", volumeID)
-	resp, err := apiClient.FindVolume(volumeID)
+	resp, err := findAttachedVolume(m, apiClient, volumeID)
 	if err != nil {
-		if resp == nil {
-			d.SetId("")
-			return nil
+		if utils.IsNotFoundError(err) {
+			return removeMissingResource(d, m, "volume attachment")
 		}
 
-		return diag.Errorf("[ERR] failed retrieving the volume: %s", err)
+		return utils.DiagError("[ERR] failed retrieving the volume", err)
 	}
 
-	if resp.InstanceID == "" || resp.InstanceID != instanceID {
+	if resp.InstanceID == instanceID {
+		d.Set("region", utils.NormalizeRegion(apiClient.Region))
+		d.Set("device_path", resp.MountPoint)
+		return nil
+	}
+
+	if resp.InstanceID == "" {
 		log.Printf("[DEBUG] Volume Attachment (%s) not found, removing from state", d.Id())
-		d.SetId("")
+		return removeMissingResource(d, m, "volume attachment")
 	}
 
-	return nil
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
+
+	// the volume was moved to another instance outside of terraform, we keep
+	// the instance it is attached to in the state, so the plan shows the change
+	// of instance_id and attach the volume again to the expected instance
+	log.Printf("[WARN] the volume %s is attached to the instance %s instead of %s", volumeID, resp.InstanceID, instanceID)
+	d.Set("instance_id", resp.InstanceID)
+	d.Set("device_path", "")
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The volume %s was attached to another instance outside of terraform", volumeID),
+		Detail: fmt.Sprintf("The volume %s is attached to the instance %s instead of %s. "+
+			"The next apply detaches it and attaches it again to the instance %s", volumeID, resp.InstanceID, instanceID, instanceID),
+	}}
+}
+
+// function to update the volume attachment, only force_detach can change and
+// it is only kept in the state
+func resourceVolumeAttachmentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceVolumeAttachmentRead(ctx, d, m)
 }
 
 // function to delete the volume
 func resourceVolumeAttachmentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*civogo.Client)
+	apiClient := m.(*providerMeta).Client()
 
 	// overwrite the region if it's defined
 	if region, ok := d.GetOk("region"); ok {
@@ -117,11 +182,213 @@ func resourceVolumeAttachmentDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	volumeID := d.Get("volume_id").(string)
+	instanceID := d.Get("instance_id").(string)
+
+	defer invalidateAttachedVolumes(m, apiClient)
 
 	log.Printf("[INFO] Detaching the volume %s", d.Id())
 	_, err := apiClient.DetachVolume(volumeID)
 	if err != nil {
 		return diag.Errorf("[ERR] an error occurred while tring to detach the volume %s", err)
 	}
+
+	// with force_detach the normal detach only gets half of the delete timeout,
+	// the other half is left to stop the instance and detach the volume again
+	timeout := d.Timeout(schema.TimeoutDelete)
+	forceDetach := d.Get("force_detach").(bool)
+	if forceDetach {
+		timeout = timeout / 2
+	}
+
+	err = waitForVolumeDetach(ctx, apiClient, volumeID, timeout)
+	if err == nil {
+		return nil
+	}
+
+	if !forceDetach {
+		return diag.Errorf("[ERR] error waiting for volume (%s) to be detached: %s", volumeID, err)
+	}
+
+	if ctx.Err() != nil {
+		return diag.Errorf("[ERR] error waiting for volume (%s) to be detached, there is no time left to force detach it: %s", volumeID, err)
+	}
+
+	log.Printf("[WARN] ##################################################################")
+	log.Printf("[WARN] FORCE DETACHING the volume %s from the instance %s", volumeID, instanceID)
+	log.Printf("[WARN] the instance will be stopped, the filesystem of the volume can be corrupted")
+	log.Printf("[WARN] ##################################################################")
+
+	// the stop and the detach share the other half of the timeout
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete) - timeout)
+
+	stopped, err := stopInstanceAndWait(ctx, apiClient, instanceID, time.Until(deadline))
+	if err != nil {
+		return diag.Errorf("[ERR] an error occurred while tring to stop the instance %s to force detach the volume: %s", instanceID, err)
+	}
+
+	err = forceDetachVolume(ctx, apiClient, volumeID, time.Until(deadline))
+
+	// the instance is started again if it was stopped here, even when the
+	// volume is still attached, instead of being left stopped
+	if stopped {
+		log.Printf("[INFO] starting the instance %s again after the force detach", instanceID)
+		if _, startErr := apiClient.StartInstance(instanceID); startErr != nil {
+			if err != nil {
+				return diag.Errorf("[ERR] %s, and the instance %s can't be started again: %s", err, instanceID, startErr)
+			}
+			return diag.Errorf("[ERR] the volume %s was force detached, but the instance %s can't be started again: %s", volumeID, instanceID, startErr)
+		}
+	}
+
+	if err != nil {
+		return diag.Errorf("[ERR] %s", err)
+	}
+
+	return nil
+}
+
+// forceDetachVolume detach the volume from the stopped instance and wait for it
+func forceDetachVolume(ctx context.Context, apiClient *civogo.Client, volumeID string, timeout time.Duration) error {
+	if _, err := apiClient.DetachVolume(volumeID); err != nil {
+		return fmt.Errorf("an error occurred while tring to force detach the volume %s: %s", volumeID, err)
+	}
+
+	if err := waitForVolumeDetach(ctx, apiClient, volumeID, timeout); err != nil {
+		return fmt.Errorf("error waiting for volume (%s) to be force detached: %s", volumeID, err)
+	}
+
 	return nil
 }
+
+// function to import the volume attachment, the ID is region:instance_id:volume_id
+func resourceVolumeAttachmentImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	region, instanceID, volumeID, err := parseVolumeAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), region)
+
+	if _, err := apiClient.FindRegion(region); err != nil {
+		return nil, fmt.Errorf("[ERR] the region %s of the volume attachment is not valid: %s", region, err)
+	}
+
+	log.Printf("[INFO] retrieving the volume %s", volumeID)
+	volume, err := apiClient.FindVolume(volumeID)
+	if err != nil {
+		return nil, fmt.Errorf("[ERR] the volume %s was not found in the region %s: %s", volumeID, region, err)
+	}
+
+	if volume.InstanceID != instanceID {
+		return nil, fmt.Errorf("[ERR] the volume %s is not attached to the instance %s", volumeID, instanceID)
+	}
+
+	d.Set("instance_id", instanceID)
+	d.Set("volume_id", volumeID)
+	d.Set("region", region)
+	d.Set("force_detach", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// volumeAttachmentID build the ID of the attachment, the region is part of it
+// so the IDs are unique across regions
+func volumeAttachmentID(apiClient *civogo.Client, instanceID string, volumeID string) (string, error) {
+	region := apiClient.Region
+	if region == "" {
+		defaultRegion, err := apiClient.GetDefaultRegion()
+		if err != nil {
+			return "", err
+		}
+		region = defaultRegion.Code
+	}
+
+	return fmt.Sprintf("%s:%s:%s", utils.NormalizeRegion(region), instanceID, volumeID), nil
+}
+
+// parseVolumeAttachmentID returns the region, instance and volume of the attachment ID
+func parseVolumeAttachmentID(id string) (string, string, string, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%s), expected region:instance_id:volume_id", id)
+	}
+
+	return utils.NormalizeRegion(parts[0]), parts[1], parts[2], nil
+}
+
+// findAttachedVolume returns the volume of an attachment, from the volume cache
+// if the provider was configured with cache_volume_reads
+func findAttachedVolume(m interface{}, apiClient *civogo.Client, volumeID string) (*civogo.Volume, error) {
+	if cache := m.(*providerMeta).volumeReadCache; cache != nil {
+		return cache.FindVolume(apiClient, volumeID)
+	}
+	return apiClient.FindVolume(volumeID)
+}
+
+// invalidateAttachedVolumes drop the cached volumes of the region after an
+// attach or a detach, so the next read doesn't get the volumes before it
+func invalidateAttachedVolumes(m interface{}, apiClient *civogo.Client) {
+	if cache := m.(*providerMeta).volumeReadCache; cache != nil {
+		cache.Invalidate(apiClient)
+	}
+}
+
+// waitForVolumeDetach wait until the volume is not attached to any instance
+func waitForVolumeDetach(ctx context.Context, apiClient *civogo.Client, volumeID string, timeout time.Duration) error {
+	detachStateConf := &resource.StateChangeConf{
+		Pending: []string{"attached"},
+		Target:  []string{"detached"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.FindVolume(volumeID)
+			if err != nil {
+				return 0, "", err
+			}
+			if resp.InstanceID != "" {
+				return resp, "attached", nil
+			}
+			return resp, "detached", nil
+		},
+		Timeout:    timeout,
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	_, err := detachStateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// stopInstanceAndWait stop the instance and wait until is stopped, it returns
+// false if the instance was already stopped
+func stopInstanceAndWait(ctx context.Context, apiClient *civogo.Client, instanceID string, timeout time.Duration) (bool, error) {
+	instance, err := apiClient.GetInstance(instanceID)
+	if err != nil {
+		return false, err
+	}
+
+	if instance.Status == "SHUTOFF" {
+		return false, nil
+	}
+
+	if _, err := apiClient.StopInstance(instanceID); err != nil {
+		return false, err
+	}
+
+	stopStateConf := &resource.StateChangeConf{
+		Pending: []string{"ACTIVE", "STOPPING"},
+		Target:  []string{"SHUTOFF"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetInstance(instanceID)
+			if err != nil {
+				return 0, "", err
+			}
+			return resp, resp.Status, nil
+		},
+		Timeout:    timeout,
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := stopStateConf.WaitForStateContext(ctx); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
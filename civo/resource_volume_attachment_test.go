@@ -1,12 +1,19 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -36,6 +43,7 @@ func TestAccCivoVolumeAttachment_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resName, "id"),
 					resource.TestCheckResourceAttrSet(resName, "instance_id"),
 					resource.TestCheckResourceAttrSet(resName, "volume_id"),
+					resource.TestCheckResourceAttr(resName, "force_detach", "false"),
 				),
 			},
 		},
@@ -70,3 +78,210 @@ resource "civo_volume_attachment" "foobar" {
 }
 `, name, name)
 }
+
+// volumeAttachmentTestClient returns a client for a fake API with the volume
+// 67890 attached to the instance
+func volumeAttachmentTestClient(t *testing.T, instanceID string) (*civogo.Client, func()) {
+	volume := fmt.Sprintf(`{"id": "67890", "name": "data", "instance_id": %q, "mountpoint": "/dev/vdb", "status": "attached"}`, instanceID)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/volumes":
+			fmt.Fprintf(rw, "[%s]", volume)
+		case "/v2/volumes/67890":
+			fmt.Fprint(rw, volume)
+		case "/v2/regions":
+			fmt.Fprint(rw, `[{"code": "LON1", "name": "London 1", "default": true}]`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		server.Close()
+		t.Fatalf("err: %s", err)
+	}
+
+	return client, server.Close
+}
+
+func TestResourceVolumeAttachmentRead_attachedToOtherInstance(t *testing.T) {
+	client, closeServer := volumeAttachmentTestClient(t, "other")
+	defer closeServer()
+
+	d := resourceVolumeAttachment().TestResourceData()
+	d.SetId("LON1:12345:67890")
+	d.Set("instance_id", "12345")
+	d.Set("volume_id", "67890")
+
+	diags := resourceVolumeAttachmentRead(context.Background(), d, &providerMeta{client: client})
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning about the other instance, got %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the attachment to be kept in the state")
+	}
+	if d.Get("instance_id").(string) != "other" {
+		t.Fatalf("expected the instance_id to be the instance the volume is attached to, got %s", d.Get("instance_id").(string))
+	}
+}
+
+func TestResourceVolumeAttachmentRead(t *testing.T) {
+	cases := []struct {
+		name       string
+		instanceID string
+		kept       bool
+		warning    bool
+		devicePath string
+	}{
+		{"attached", "12345", true, false, "/dev/vdb"},
+		{"not attached", "", false, false, ""},
+		{"moved to another instance", "other", true, true, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, closeServer := volumeAttachmentTestClient(t, c.instanceID)
+			defer closeServer()
+
+			d := resourceVolumeAttachment().TestResourceData()
+			d.SetId("LON1:12345:67890")
+			d.Set("instance_id", "12345")
+			d.Set("volume_id", "67890")
+
+			diags := resourceVolumeAttachmentRead(context.Background(), d, &providerMeta{client: client})
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if got := len(diags) == 1 && diags[0].Severity == diag.Warning; got != c.warning {
+				t.Fatalf("expected a warning %t, got %v", c.warning, diags)
+			}
+			if got := d.Id() != ""; got != c.kept {
+				t.Fatalf("expected the attachment to be kept %t, got ID %q", c.kept, d.Id())
+			}
+			if path := d.Get("device_path").(string); path != c.devicePath {
+				t.Fatalf("expected the device %q, got %q", c.devicePath, path)
+			}
+		})
+	}
+}
+
+// forceDetachTestClient returns a client for a fake API where the volume 67890
+// doesn't get detached from the running instance 12345, and the calls it
+// received. The detach of the stopped instance fails unless detached is set
+func forceDetachTestClient(t *testing.T, detached bool) (*civogo.Client, *[]string, func()) {
+	calls := []string{}
+	status := "ACTIVE"
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/volumes/67890/detach":
+			calls = append(calls, "detach")
+			if status == "SHUTOFF" && !detached {
+				rw.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(rw, `{"code": "internal_error", "reason": "failed"}`)
+				return
+			}
+			fmt.Fprint(rw, `{"result": "success"}`)
+		case "/v2/volumes":
+			// the volume only gets detached from the stopped instance
+			if status != "SHUTOFF" {
+				rw.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(rw, `{"code": "internal_error", "reason": "failed"}`)
+				return
+			}
+			fmt.Fprint(rw, `[{"id": "67890", "name": "data", "instance_id": ""}]`)
+		case "/v2/instances/12345":
+			fmt.Fprintf(rw, `{"id": "12345", "status": %q}`, status)
+		case "/v2/instances/12345/stop":
+			calls = append(calls, "stop")
+			status = "SHUTOFF"
+			fmt.Fprint(rw, `{"result": "success"}`)
+		case "/v2/instances/12345/start":
+			calls = append(calls, "start")
+			status = "ACTIVE"
+			fmt.Fprint(rw, `{"result": "success"}`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		server.Close()
+		t.Fatalf("err: %s", err)
+	}
+
+	return client, &calls, server.Close
+}
+
+func TestResourceVolumeAttachmentDelete_forceDetach(t *testing.T) {
+	newData := func() *schema.ResourceData {
+		d := resourceVolumeAttachment().TestResourceData()
+		d.SetId("LON1:12345:67890")
+		d.Set("instance_id", "12345")
+		d.Set("volume_id", "67890")
+		d.Set("force_detach", true)
+		return d
+	}
+
+	t.Run("no time left", func(t *testing.T) {
+		client, calls, closeServer := forceDetachTestClient(t, false)
+		defer closeServer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		diags := resourceVolumeAttachmentDelete(ctx, newData(), &providerMeta{client: client})
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "there is no time left to force detach it") {
+			t.Fatalf("expected an error about the expired timeout, got %v", diags)
+		}
+		if strings.Join(*calls, ",") != "detach" {
+			t.Fatalf("expected the instance to not be stopped, got the calls %v", *calls)
+		}
+	})
+
+	t.Run("failed force detach", func(t *testing.T) {
+		client, calls, closeServer := forceDetachTestClient(t, false)
+		defer closeServer()
+
+		diags := resourceVolumeAttachmentDelete(context.Background(), newData(), &providerMeta{client: client})
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "an error occurred while tring to force detach the volume 67890") {
+			t.Fatalf("expected an error about the force detach, got %v", diags)
+		}
+		if strings.Join(*calls, ",") != "detach,stop,detach,start" {
+			t.Fatalf("expected the instance to be started again, got the calls %v", *calls)
+		}
+	})
+
+	t.Run("force detached", func(t *testing.T) {
+		client, calls, closeServer := forceDetachTestClient(t, true)
+		defer closeServer()
+
+		if diags := resourceVolumeAttachmentDelete(context.Background(), newData(), &providerMeta{client: client}); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if strings.Join(*calls, ",") != "detach,stop,detach,start" {
+			t.Fatalf("expected the instance to be started again, got the calls %v", *calls)
+		}
+	})
+}
+
+func TestResourceVolumeAttachmentRead_cachedMissingVolume(t *testing.T) {
+	client, closeServer := volumeAttachmentTestClient(t, "12345")
+	defer closeServer()
+
+	meta := &providerMeta{client: client, volumeReadCache: utils.NewVolumeCache(volumeReadCacheTTL)}
+
+	d := resourceVolumeAttachment().TestResourceData()
+	d.SetId("LON1:12345:missing")
+	d.Set("instance_id", "12345")
+	d.Set("volume_id", "missing")
+
+	if diags := resourceVolumeAttachmentRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected the attachment of a deleted volume to be removed from the state, got ID %s", d.Id())
+	}
+}
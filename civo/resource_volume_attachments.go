@@ -0,0 +1,239 @@
+package civo
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Volume attachments resource, with this we can attach many volumes to the
+// same instance without declaring one civo_volume_attachment for every volume
+func resourceVolumeAttachments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the attachment/detachment of many volumes to the same instance.",
+		Schema: map[string]*schema.Schema{
+			"instance_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The ID of target instance for attachment",
+			},
+			"volume_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The IDs of the volumes to attach to the instance",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region for the volume attachments",
+				DiffSuppressFunc: utils.DiffSuppressRegion,
+			},
+			// Computed resource
+			"attachments": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The status of every volume in `volume_ids`",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"volume_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the volume",
+						},
+						"attached": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "If the volume is attached to the instance",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the volume",
+						},
+						"mount_point": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The mount point of the volume (from instance's perspective)",
+						},
+					},
+				},
+			},
+		},
+		CreateContext: resourceVolumeAttachmentsCreate,
+		ReadContext:   resourceVolumeAttachmentsRead,
+		UpdateContext: resourceVolumeAttachmentsUpdate,
+		DeleteContext: resourceVolumeAttachmentsDelete,
+	}
+}
+
+// function to attach all the volumes
+func resourceVolumeAttachmentsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	defer invalidateAttachedVolumes(m, apiClient)
+
+	instanceID := d.Get("instance_id").(string)
+
+	attached := []string{}
+	for _, volumeID := range expandVolumeIDs(d.Get("volume_ids").(*schema.Set)) {
+		err := attachVolumeToInstance(apiClient, volumeID, instanceID)
+		if err != nil {
+			// keep the volumes already attached in the state, so the resource
+			// is tainted and the next apply detaches them before trying again
+			if len(attached) > 0 {
+				d.SetId(resource.PrefixedUniqueId(instanceID + "-"))
+				d.Set("volume_ids", attached)
+			}
+			return diag.Errorf("[ERR] error attaching volume %s to instance %s: %s", volumeID, instanceID, err)
+		}
+		attached = append(attached, volumeID)
+	}
+
+	d.SetId(resource.PrefixedUniqueId(instanceID + "-"))
+
+	return resourceVolumeAttachmentsRead(ctx, d, m)
+}
+
+// function to read the status of all the volumes
+func resourceVolumeAttachmentsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	instanceID := d.Get("instance_id").(string)
+
+	log.Printf("[INFO] retrieving the volumes attached to the instance %s", instanceID)
+	volumes, err := apiClient.ListVolumes()
+	if err != nil {
+		return utils.DiagError("[ERR] failed retrieving the volumes", err)
+	}
+
+	volumesByID := make(map[string]civogo.Volume, len(volumes))
+	for _, volume := range volumes {
+		volumesByID[volume.ID] = volume
+	}
+
+	attachedIDs := []string{}
+	attachments := []map[string]interface{}{}
+	for _, volumeID := range expandVolumeIDs(d.Get("volume_ids").(*schema.Set)) {
+		volume, ok := volumesByID[volumeID]
+		if !ok {
+			log.Printf("[DEBUG] Volume (%s) not found, removing from the attachments", volumeID)
+			continue
+		}
+
+		attached := volume.InstanceID == instanceID
+		if attached {
+			attachedIDs = append(attachedIDs, volumeID)
+		}
+
+		attachments = append(attachments, map[string]interface{}{
+			"volume_id":   volume.ID,
+			"attached":    attached,
+			"status":      volume.Status,
+			"mount_point": volume.MountPoint,
+		})
+	}
+
+	if len(attachedIDs) == 0 {
+		log.Printf("[DEBUG] Volume Attachments (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("volume_ids", attachedIDs)
+	d.Set("attachments", attachments)
+	d.Set("region", utils.NormalizeRegion(apiClient.Region))
+
+	return nil
+}
+
+// function to attach and detach the volumes that changed
+func resourceVolumeAttachmentsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	defer invalidateAttachedVolumes(m, apiClient)
+
+	instanceID := d.Get("instance_id").(string)
+
+	if d.HasChange("volume_ids") {
+		oldIDs, newIDs := d.GetChange("volume_ids")
+		toDetach := oldIDs.(*schema.Set).Difference(newIDs.(*schema.Set))
+		toAttach := newIDs.(*schema.Set).Difference(oldIDs.(*schema.Set))
+
+		for _, volumeID := range expandVolumeIDs(toDetach) {
+			log.Printf("[INFO] Detaching the volume %s", volumeID)
+			_, err := apiClient.DetachVolume(volumeID)
+			if err != nil {
+				return diag.Errorf("[ERR] an error occurred while tring to detach the volume %s, %s", volumeID, err)
+			}
+		}
+
+		for _, volumeID := range expandVolumeIDs(toAttach) {
+			err := attachVolumeToInstance(apiClient, volumeID, instanceID)
+			if err != nil {
+				return diag.Errorf("[ERR] error attaching volume %s to instance %s: %s", volumeID, instanceID, err)
+			}
+		}
+	}
+
+	return resourceVolumeAttachmentsRead(ctx, d, m)
+}
+
+// function to detach all the volumes
+func resourceVolumeAttachmentsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := utils.RegionScopedClient(m.(*providerMeta).Client(), d.Get("region").(string))
+
+	defer invalidateAttachedVolumes(m, apiClient)
+
+	for _, volumeID := range expandVolumeIDs(d.Get("volume_ids").(*schema.Set)) {
+		log.Printf("[INFO] Detaching the volume %s", volumeID)
+		_, err := apiClient.DetachVolume(volumeID)
+		if err != nil {
+			return diag.Errorf("[ERR] an error occurred while tring to detach the volume %s, %s", volumeID, err)
+		}
+	}
+
+	return nil
+}
+
+// attachVolumeToInstance attach the volume only if it's not already attached to the instance
+func attachVolumeToInstance(apiClient *civogo.Client, volumeID, instanceID string) error {
+	log.Printf("[INFO] retrieving the volume %s", volumeID)
+	volume, err := apiClient.FindVolume(volumeID)
+	if err != nil {
+		return err
+	}
+
+	if volume.InstanceID == instanceID {
+		return nil
+	}
+
+	log.Printf("[INFO] attaching the volume %s to instance %s", volumeID, instanceID)
+	_, err = apiClient.AttachVolume(volumeID, instanceID)
+	return err
+}
+
+// expandVolumeIDs convert the set of volume IDs in a sorted slice
+func expandVolumeIDs(set *schema.Set) []string {
+	volumeIDs := make([]string, 0, set.Len())
+	for _, volumeID := range set.List() {
+		volumeIDs = append(volumeIDs, volumeID.(string))
+	}
+	sort.Strings(volumeIDs)
+	return volumeIDs
+}
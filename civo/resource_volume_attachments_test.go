@@ -0,0 +1,161 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCivoVolumeAttachments_basic(t *testing.T) {
+	var instance civogo.Instance
+
+	// generate a random name for each test run
+	resName := "civo_volume_attachments.foobar"
+	var volumeAttachmentsName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCivoVolumeAttachmentsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckCivoVolumeAttachmentsConfigBasic(volumeAttachmentsName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCivoInstanceResourceExists("civo_instance.vm", &instance),
+					resource.TestCheckResourceAttrSet(resName, "id"),
+					resource.TestCheckResourceAttr(resName, "volume_ids.#", "2"),
+					resource.TestCheckResourceAttr(resName, "attachments.#", "2"),
+					resource.TestCheckResourceAttr(resName, "attachments.0.attached", "true"),
+					resource.TestCheckResourceAttr(resName, "attachments.1.attached", "true"),
+				),
+			},
+			{
+				Config: testAccCheckCivoVolumeAttachmentsConfigUpdates(volumeAttachmentsName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "volume_ids.#", "1"),
+					resource.TestCheckResourceAttr(resName, "attachments.#", "1"),
+					resource.TestCheckResourceAttrPair(resName, "attachments.0.volume_id", "civo_volume.foo", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckCivoVolumeAttachmentsDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).Client()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "civo_volume_attachments" {
+			continue
+		}
+
+		for key, value := range rs.Primary.Attributes {
+			if key == "volume_ids.#" || !strings.HasPrefix(key, "volume_ids.") {
+				continue
+			}
+
+			volume, err := client.FindVolume(value)
+			if err == nil && volume.InstanceID == rs.Primary.Attributes["instance_id"] {
+				return fmt.Errorf("Volume %s still attached", value)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckCivoVolumeAttachmentsConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "civo_instance" "vm" {
+	hostname = "instance-%s"
+}
+
+resource "civo_volume" "foo" {
+	name = "%s-foo"
+	size_gb = 10
+	network_id = civo_instance.vm.network_id
+}
+
+resource "civo_volume" "bar" {
+	name = "%s-bar"
+	size_gb = 10
+	network_id = civo_instance.vm.network_id
+}
+
+resource "civo_volume_attachments" "foobar" {
+	instance_id = civo_instance.vm.id
+	volume_ids  = [civo_volume.foo.id, civo_volume.bar.id]
+}
+`, name, name, name)
+}
+
+func testAccCheckCivoVolumeAttachmentsConfigUpdates(name string) string {
+	return fmt.Sprintf(`
+resource "civo_instance" "vm" {
+	hostname = "instance-%s"
+}
+
+resource "civo_volume" "foo" {
+	name = "%s-foo"
+	size_gb = 10
+	network_id = civo_instance.vm.network_id
+}
+
+resource "civo_volume" "bar" {
+	name = "%s-bar"
+	size_gb = 10
+	network_id = civo_instance.vm.network_id
+}
+
+resource "civo_volume_attachments" "foobar" {
+	instance_id = civo_instance.vm.id
+	volume_ids  = [civo_volume.foo.id]
+}
+`, name, name, name)
+}
+
+func TestResourceVolumeAttachmentsCreate_partial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/volumes":
+			fmt.Fprint(rw, `[{"id": "vol-a", "name": "a"}, {"id": "vol-b", "name": "b"}]`)
+		case "/v2/volumes/vol-a/attach":
+			fmt.Fprint(rw, `{"result": "success"}`)
+		case "/v2/volumes/vol-b/attach":
+			rw.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(rw, `{"code": "internal_error", "reason": "failed"}`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceVolumeAttachments().TestResourceData()
+	d.Set("instance_id", "12345")
+	d.Set("volume_ids", []string{"vol-a", "vol-b"})
+
+	diags := resourceVolumeAttachmentsCreate(context.Background(), d, &providerMeta{client: client})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "error attaching volume vol-b") {
+		t.Fatalf("expected an error attaching vol-b, got %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the attachments to be kept in the state after the partial attach")
+	}
+	if ids := expandVolumeIDs(d.Get("volume_ids").(*schema.Set)); strings.Join(ids, ",") != "vol-a" {
+		t.Fatalf("expected only vol-a in the state, got %v", ids)
+	}
+}
@@ -1,7 +1,11 @@
 package civo
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/civo/civogo"
@@ -35,6 +39,8 @@ func TestAccCivoVolume_basic(t *testing.T) {
 					// verify local values
 					resource.TestCheckResourceAttr(resName, "name", VolumeName),
 					resource.TestCheckResourceAttr(resName, "size_gb", "60"),
+					resource.TestCheckResourceAttr(resName, "bootable", "false"),
+					resource.TestCheckResourceAttr(resName, "region", testAccRegion()),
 				),
 			},
 		},
@@ -95,7 +101,7 @@ func testAccCheckCivoVolumeResourceExists(n string, volume *civogo.Volume) resou
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(*civogo.Client)
+		client := testAccProvider.Meta().(*providerMeta).Client()
 		resp, err := client.FindVolume(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Volume not found: (%s) %s", rs.Primary.ID, err)
@@ -119,7 +125,7 @@ func testAccCheckCivoVolumeUpdated(volume *civogo.Volume, name string) resource.
 }
 
 func testAccCheckCivoVolumeDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*civogo.Client)
+	client := testAccProvider.Meta().(*providerMeta).Client()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "civo_volume" {
@@ -141,7 +147,8 @@ resource "civo_volume" "foobar" {
 	name = "%s"
 	size_gb = 60
 	bootable = false
-}`, name)
+	region = "%s"
+}`, name, testAccRegion())
 }
 
 func testAccCheckCivoVolumeConfigUpdates(name string) string {
@@ -150,5 +157,88 @@ resource "civo_volume" "foobar" {
 	name = "%s"
 	size_gb = 80
 	bootable = false
-}`, name)
+	region = "%s"
+}`, name, testAccRegion())
+}
+
+func TestWaitForVolumeListed(t *testing.T) {
+	var lists int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// the new volume is only listed after the first requests
+		if atomic.AddInt32(&lists, 1) <= 2 {
+			fmt.Fprint(rw, `[]`)
+			return
+		}
+		fmt.Fprint(rw, `[{"id": "12345", "name": "data", "status": "available"}]`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	volume, err := waitForVolumeListed(context.Background(), client, "12345")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if volume.ID != "12345" {
+		t.Fatalf("expected the volume 12345, got %s", volume.ID)
+	}
+	if atomic.LoadInt32(&lists) < 3 {
+		t.Fatalf("expected to wait for the volume, it was listed %d times", lists)
+	}
+}
+
+func TestResourceVolumeDelete_detachFirst(t *testing.T) {
+	var detached int32
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method)
+		switch req.Method {
+		case http.MethodGet:
+			if atomic.LoadInt32(&detached) == 0 {
+				fmt.Fprint(rw, `[{"id": "12345", "name": "data", "instance_id": "67890", "status": "attached"}]`)
+				return
+			}
+			fmt.Fprint(rw, `[{"id": "12345", "name": "data", "status": "available"}]`)
+		case http.MethodPut:
+			atomic.StoreInt32(&detached, 1)
+			fmt.Fprint(rw, `{"result": "success"}`)
+		case http.MethodDelete:
+			if atomic.LoadInt32(&detached) == 0 {
+				rw.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(rw, `{"code": "database_volume_still_attached", "reason": "the volume is still attached"}`)
+				return
+			}
+			fmt.Fprint(rw, `{"result": "success"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceVolume().TestResourceData()
+	d.SetId("12345")
+	if diags := resourceVolumeDelete(context.Background(), d, &providerMeta{client: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if requests[len(requests)-1] != http.MethodDelete {
+		t.Fatalf("expected the volume to be deleted after the detach, got the requests %v", requests)
+	}
+}
+
+func TestResourceVolumeDelete_deletionProtection(t *testing.T) {
+	d := resourceVolume().TestResourceData()
+	d.SetId("12345")
+	d.Set("deletion_protection", true)
+
+	// the protection is checked before any request, so the client is never used
+	diags := resourceVolumeDelete(context.Background(), d, &civogo.Client{})
+	if !diags.HasError() {
+		t.Fatal("expected an error with deletion_protection")
+	}
 }
@@ -0,0 +1,8 @@
+data "civo_default_network" "default" {
+  region = "LON1"
+}
+
+resource "civo_firewall" "www" {
+  name       = "www"
+  network_id = data.civo_default_network.default.id
+}
@@ -0,0 +1,16 @@
+data "civo_dns_domain" "domain" {
+    name = "domain.com"
+}
+
+# Add a record to a domain managed elsewhere
+resource "civo_dns_domain_record" "www" {
+    domain_id = data.civo_dns_domain.domain.id
+    type = "A"
+    name = "www"
+    value = "10.10.10.1"
+    ttl = 600
+}
+
+output "domain_records" {
+  value = data.civo_dns_domain.domain.records
+}
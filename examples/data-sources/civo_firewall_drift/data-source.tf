@@ -0,0 +1,27 @@
+data "civo_firewall_drift" "www" {
+  firewall_id = "b8ecd2ab-2267-4a5e-8692-cbf1d32583e3"
+
+  expected_rule {
+    label      = "ssh"
+    start_port = "22"
+    cidr       = ["192.168.1.2/32"]
+    direction  = "ingress"
+    action     = "allow"
+  }
+
+  expected_rule {
+    label      = "https"
+    start_port = "443"
+    cidr       = ["0.0.0.0/0"]
+    direction  = "ingress"
+    action     = "allow"
+  }
+}
+
+output "firewall_in_sync" {
+  value = data.civo_firewall_drift.www.in_sync
+}
+
+output "firewall_extra_rules" {
+  value = [for rule in data.civo_firewall_drift.www.extra : rule.id]
+}
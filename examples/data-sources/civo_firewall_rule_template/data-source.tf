@@ -0,0 +1,42 @@
+provider "civo" {
+  rule_template {
+    name = "web"
+
+    rule {
+      label      = "http"
+      start_port = "80"
+      cidr       = ["0.0.0.0/0"]
+      direction  = "ingress"
+      action     = "allow"
+    }
+
+    rule {
+      label      = "https"
+      start_port = "443"
+      cidr       = ["0.0.0.0/0"]
+      direction  = "ingress"
+      action     = "allow"
+    }
+  }
+}
+
+data "civo_firewall_rule_template" "web" {
+  name = "web"
+}
+
+resource "civo_firewall" "www" {
+  name = "www"
+}
+
+resource "civo_firewall_rule" "web" {
+  for_each = { for rule in data.civo_firewall_rule_template.web.rules : rule.label => rule }
+
+  firewall_id = civo_firewall.www.id
+  label       = each.value.label
+  protocol    = each.value.protocol
+  start_port  = each.value.start_port
+  end_port    = each.value.end_port != "" ? each.value.end_port : null
+  cidr        = each.value.cidr
+  direction   = each.value.direction
+  action      = each.value.action
+}
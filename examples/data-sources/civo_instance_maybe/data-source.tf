@@ -0,0 +1,15 @@
+data "civo_instance_maybe" "backup" {
+    hostname = "backup.example.com"
+}
+
+resource "civo_firewall_rule" "backup" {
+    count = data.civo_instance_maybe.backup.exists ? 1 : 0
+    firewall_id = civo_firewall.www.id
+    protocol = "tcp"
+    start_port = "22"
+    end_port = "22"
+    cidr = [format("%s/%s", data.civo_instance_maybe.backup.public_ip, "32")]
+    direction = "ingress"
+    action = "allow"
+    label = "backup-ssh"
+}
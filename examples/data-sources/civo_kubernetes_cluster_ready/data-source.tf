@@ -0,0 +1,21 @@
+resource "civo_kubernetes_cluster" "my-cluster" {
+    name = "my-cluster"
+    firewall_id = civo_firewall.my-firewall.id
+    pools {
+        size = "g4s.kube.medium"
+        node_count = 3
+    }
+}
+
+# Wait until the 3 nodes are ready
+data "civo_kubernetes_cluster_ready" "my-cluster" {
+    cluster_id = civo_kubernetes_cluster.my-cluster.id
+
+    timeouts {
+        read = "15m"
+    }
+}
+
+output "cluster_ready" {
+  value = data.civo_kubernetes_cluster_ready.my-cluster.ready
+}
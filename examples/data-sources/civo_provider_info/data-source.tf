@@ -0,0 +1,11 @@
+# Add the versions in use to an issue report
+data "civo_provider_info" "current" {
+}
+
+output "civo_versions" {
+  value = {
+    provider = data.civo_provider_info.current.provider_version
+    civogo   = data.civo_provider_info.current.civogo_version
+    api      = data.civo_provider_info.current.api_version
+  }
+}
@@ -0,0 +1,8 @@
+data "civo_reserved_ip" "www" {
+    name = "www-ip"
+}
+
+resource "civo_instance" "www" {
+    hostname = "www.example.com"
+    reserved_ip = data.civo_reserved_ip.www.id
+}
@@ -0,0 +1,11 @@
+# Fail fast if the token or the region are not valid
+data "civo_whoami" "current" {
+}
+
+output "civo_account_id" {
+  value = data.civo_whoami.current.account_id
+}
+
+output "civo_region" {
+  value = data.civo_whoami.current.region
+}
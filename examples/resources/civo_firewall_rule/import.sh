@@ -1,2 +1,5 @@
 # using firewall_id:firewall_rule_id
 terraform import civo_firewall_rule.http b8ecd2ab-2267-4a5e-8692-cbf1d32583e3:4b0022ee-00b2-4f81-a40d-b4f8728923a7
+
+# using firewall_id:* to import all the rules of the firewall
+terraform import civo_firewall_rule.imported 'b8ecd2ab-2267-4a5e-8692-cbf1d32583e3:*'
@@ -49,5 +49,6 @@ resource "civo_firewall_rule" "custom_port" {
     cidr = [format("%s/%s",civo_instance.foo.public_ip,"32")]
     direction = "ingress"
     label = "custom-application"
+    description = "Allow the custom application from the instance foo"
     depends_on = [civo_firewall.custom_firewall]
 }
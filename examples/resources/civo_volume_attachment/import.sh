@@ -0,0 +1,2 @@
+# using region:instance_id:volume_id
+terraform import civo_volume_attachment.foobar LON1:b8ecd2ab-2267-4a5e-8692-cbf1d32583e3:4b0022ee-00b2-4f81-a40d-b4f8728923a7
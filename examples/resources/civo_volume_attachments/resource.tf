@@ -0,0 +1,23 @@
+# Create a new instance
+resource "civo_instance" "foo" {
+    hostname = "foo.com"
+}
+
+# Create the volumes
+resource "civo_volume" "data" {
+    name = "data"
+    size_gb = 20
+    network_id = civo_instance.foo.network_id
+}
+
+resource "civo_volume" "logs" {
+    name = "logs"
+    size_gb = 10
+    network_id = civo_instance.foo.network_id
+}
+
+# Attach both volumes to the instance
+resource "civo_volume_attachments" "foo" {
+  instance_id = civo_instance.foo.id
+  volume_ids  = [civo_volume.data.id, civo_volume.logs.id]
+}
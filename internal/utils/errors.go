@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// civogo wraps the errors as "Code: message", the code is always a single word
+var errorCodeRegexp = regexp.MustCompile(`^[A-Z][A-Za-z]+$`)
+
+// ErrorCode returns the civogo error code of the error, like ZeroMatchesError
+// or QuotaLimitReachedError, if the error don't have a code it returns an empty string
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var httpErr civogo.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Sprintf("HTTP%d", httpErr.Code)
+	}
+
+	parts := strings.SplitN(err.Error(), ": ", 2)
+	if len(parts) == 2 && errorCodeRegexp.MatchString(parts[0]) {
+		return parts[0]
+	}
+
+	return ""
+}
+
+// IsNotFoundError check if the error returned by civogo means the object doesn't exist
+func IsNotFoundError(err error) bool {
+	code := ErrorCode(err)
+	return code == "ZeroMatchesError" || code == "HTTP404" || strings.HasSuffix(code, "NotFoundError") || strings.HasSuffix(code, "NotFound")
+}
+
+// IsQuotaError check if the error returned by civogo is because the quota of the account was reached
+func IsQuotaError(err error) bool {
+	return ErrorCode(err) == "QuotaLimitReachedError"
+}
+
+// IsRateLimitError check if the error returned by civogo is because the API is rate limiting the requests,
+// civogo don't have a code for it so we look for the HTTP status code
+func IsRateLimitError(err error) bool {
+	return err != nil && (ErrorCode(err) == "HTTP429" || strings.Contains(err.Error(), "code: 429"))
+}
+
+// IsAuthenticationError check if the error returned by civogo is because the token is not valid
+func IsAuthenticationError(err error) bool {
+	return strings.HasPrefix(ErrorCode(err), "Authentication")
+}
+
+// friendlyErrorSummary returns a message for the errors that the user can fix
+func friendlyErrorSummary(err error) string {
+	switch {
+	case IsNotFoundError(err):
+		return "the object was not found, check the ID/name and the region"
+	case IsQuotaError(err):
+		return "the quota of your account has been reached, remove unused objects or ask for a quota increase"
+	case IsRateLimitError(err):
+		return "too many requests were sent to the Civo API, try again in a few minutes"
+	case IsAuthenticationError(err):
+		return "the authentication failed, check the token used by the provider"
+	}
+	return ""
+}
+
+// DiagError build the diagnostic for an error returned by civogo, the summary is
+// the message plus the error (or a friendly version for the known errors) and the
+// detail has the civogo error code so it can be used to branch on the error
+func DiagError(message string, err error) diag.Diagnostics {
+	summary := fmt.Sprintf("%s: %s", message, err)
+	if friendly := friendlyErrorSummary(err); friendly != "" {
+		summary = fmt.Sprintf("%s: %s", message, friendly)
+	}
+
+	var detail string
+	if code := ErrorCode(err); code != "" {
+		detail = fmt.Sprintf("civo error code: %s\n\n%s", code, err)
+	}
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   detail,
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+)
+
+func TestErrorCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code string
+	}{
+		{nil, ""},
+		{errors.New("something went wrong"), ""},
+		{fmt.Errorf("ZeroMatchesError: unable to find foo, zero matches"), "ZeroMatchesError"},
+		{fmt.Errorf("QuotaLimitReachedError: quota reached"), "QuotaLimitReachedError"},
+		{fmt.Errorf("failed to create: quota reached"), ""},
+		{civogo.HTTPError{Code: 429, Status: "429 Too Many Requests"}, "HTTP429"},
+	}
+
+	for _, c := range cases {
+		if code := ErrorCode(c.err); code != c.code {
+			t.Errorf("ErrorCode(%v): expected %q, got %q", c.err, c.code, code)
+		}
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	notFound := fmt.Errorf("DatabaseInstanceNotFoundError: instance not found")
+	if !IsNotFoundError(notFound) {
+		t.Errorf("expected %v to be a not found error", notFound)
+	}
+
+	quota := fmt.Errorf("QuotaLimitReachedError: quota reached")
+	if !IsQuotaError(quota) || IsNotFoundError(quota) {
+		t.Errorf("expected %v to be only a quota error", quota)
+	}
+
+	rateLimit := fmt.Errorf("CommonError: Unknown error response - status: 429 Too Many Requests, code: 429, reason: slow down")
+	if !IsRateLimitError(rateLimit) {
+		t.Errorf("expected %v to be a rate limit error", rateLimit)
+	}
+
+	auth := fmt.Errorf("AuthenticationFailedError: bad token")
+	if !IsAuthenticationError(auth) {
+		t.Errorf("expected %v to be an authentication error", auth)
+	}
+}
+
+func TestDiagError(t *testing.T) {
+	diags := DiagError("[ERR] failed to create a new volume", fmt.Errorf("QuotaLimitReachedError: quota reached"))
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %d", len(diags))
+	}
+
+	if !strings.Contains(diags[0].Summary, "quota of your account") {
+		t.Errorf("expected a friendly summary, got %q", diags[0].Summary)
+	}
+
+	if !strings.Contains(diags[0].Detail, "civo error code: QuotaLimitReachedError") {
+		t.Errorf("expected the error code in the detail, got %q", diags[0].Detail)
+	}
+
+	diags = DiagError("[ERR] failed to create a new volume", errors.New("boom"))
+	if diags[0].Summary != "[ERR] failed to create a new volume: boom" || diags[0].Detail != "" {
+		t.Errorf("unexpected diagnostic for an error without code: %+v", diags[0])
+	}
+}
@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/civo/civogo"
+)
+
+// SortFirewallRules sort the rules by direction, protocol, start port and
+// cidr, and the cidr of every rule, so the rules are always in the same order
+// no matter the order the API returns them. The rules are sorted in place
+func SortFirewallRules(rules []civogo.FirewallRule) {
+	for i := range rules {
+		rules[i].Cidr = SortedCidr(rules[i].Cidr)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.StartPort != b.StartPort {
+			return comparePorts(a.StartPort, b.StartPort)
+		}
+		if cidrA, cidrB := firstCidr(a), firstCidr(b); cidrA != cidrB {
+			return cidrA < cidrB
+		}
+		return a.ID < b.ID
+	})
+}
+
+// SortedCidr returns a sorted copy of the cidr list
+func SortedCidr(cidr []string) []string {
+	sorted := append([]string{}, cidr...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// comparePorts compare the ports as numbers, the empty or not numeric ports
+// (like the icmp rules) go first
+func comparePorts(a, b string) bool {
+	portA, errA := strconv.Atoi(a)
+	portB, errB := strconv.Atoi(b)
+	switch {
+	case errA != nil && errB != nil:
+		return a < b
+	case errA != nil:
+		return true
+	case errB != nil:
+		return false
+	}
+	return portA < portB
+}
+
+func firstCidr(rule civogo.FirewallRule) string {
+	if len(rule.Cidr) == 0 {
+		return ""
+	}
+	return rule.Cidr[0]
+}
+
+// FirewallRuleShadowedBy check if the rule is fully covered by the existing
+// rule, same direction, action and protocol, and the ports and every cidr of
+// the rule are inside the ports and the cidr of the existing rule
+func FirewallRuleShadowedBy(rule, existing civogo.FirewallRule) bool {
+	if rule.Direction != existing.Direction || rule.Action != existing.Action || rule.Protocol != existing.Protocol {
+		return false
+	}
+
+	if rule.Protocol != "icmp" {
+		start, end, ok := portRange(rule)
+		if !ok {
+			return false
+		}
+		existingStart, existingEnd, ok := portRange(existing)
+		if !ok || start < existingStart || end > existingEnd {
+			return false
+		}
+	}
+
+	if len(rule.Cidr) == 0 {
+		return false
+	}
+
+	for _, cidr := range rule.Cidr {
+		covered := false
+		for _, existingCidr := range existing.Cidr {
+			if CidrContains(existingCidr, cidr) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CidrContains check if the inner cidr is inside the outer cidr, a plain
+// IP address is taken as a /32 (or /128 for IPv6)
+func CidrContains(outer, inner string) bool {
+	outerNet, ok := parseCidr(outer)
+	if !ok {
+		return false
+	}
+	innerNet, ok := parseCidr(inner)
+	if !ok {
+		return false
+	}
+
+	outerOnes, outerBits := outerNet.Mask.Size()
+	innerOnes, innerBits := innerNet.Mask.Size()
+	if outerBits != innerBits || innerOnes < outerOnes {
+		return false
+	}
+
+	return outerNet.Contains(innerNet.IP)
+}
+
+func parseCidr(cidr string) (*net.IPNet, bool) {
+	if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+		return ipNet, true
+	}
+
+	ip := net.ParseIP(cidr)
+	if ip == nil {
+		return nil, false
+	}
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, true
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, true
+}
+
+// portRange returns the ports of the rule, the end port is the start port if
+// the rule has only one port
+func portRange(rule civogo.FirewallRule) (int, int, bool) {
+	start, err := strconv.Atoi(rule.StartPort)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	end := start
+	if rule.EndPort != "" {
+		if end, err = strconv.Atoi(rule.EndPort); err != nil {
+			return 0, 0, false
+		}
+	}
+
+	return start, end, true
+}
+
+// NormalizeFirewallRule returns the rule in the form used to compare the
+// rules, the ID and the label are cleared because they don't change the
+// traffic of the rule, the end port is the start port for a single port, the
+// icmp rules have no ports, and the cidr are in canonical form and sorted
+func NormalizeFirewallRule(rule civogo.FirewallRule) civogo.FirewallRule {
+	normalized := civogo.FirewallRule{
+		Protocol:  strings.ToLower(rule.Protocol),
+		StartPort: rule.StartPort,
+		EndPort:   rule.EndPort,
+		Direction: strings.ToLower(rule.Direction),
+		Action:    strings.ToLower(rule.Action),
+	}
+
+	if normalized.Protocol == "icmp" {
+		normalized.StartPort = ""
+		normalized.EndPort = ""
+	} else if normalized.EndPort == "" {
+		normalized.EndPort = normalized.StartPort
+	}
+
+	cidr := make([]string, 0, len(rule.Cidr))
+	for _, value := range rule.Cidr {
+		if ipNet, ok := parseCidr(value); ok {
+			value = ipNet.String()
+		}
+		cidr = append(cidr, value)
+	}
+	normalized.Cidr = SortedCidr(cidr)
+
+	return normalized
+}
+
+// firewallRuleKey returns a key that is the same for two rules with the same
+// normalized form
+func firewallRuleKey(rule civogo.FirewallRule) string {
+	rule = NormalizeFirewallRule(rule)
+	return strings.Join([]string{
+		rule.Direction,
+		rule.Action,
+		rule.Protocol,
+		rule.StartPort,
+		rule.EndPort,
+		strings.Join(rule.Cidr, ","),
+	}, "|")
+}
+
+// DiffFirewallRules compare the live rules of a firewall with the expected
+// rules in their normalized form. It returns the live rules that are not
+// expected and the expected rules that are not live, a rule repeated in one
+// list must be repeated the same number of times in the other. Both lists
+// are sorted
+func DiffFirewallRules(live, expected []civogo.FirewallRule) (extra, missing []civogo.FirewallRule) {
+	pending := make(map[string]int, len(expected))
+	for _, rule := range expected {
+		pending[firewallRuleKey(rule)]++
+	}
+
+	extra = []civogo.FirewallRule{}
+	for _, rule := range live {
+		key := firewallRuleKey(rule)
+		if pending[key] > 0 {
+			pending[key]--
+			continue
+		}
+		extra = append(extra, rule)
+	}
+
+	missing = []civogo.FirewallRule{}
+	for _, rule := range expected {
+		key := firewallRuleKey(rule)
+		if pending[key] > 0 {
+			pending[key]--
+			missing = append(missing, rule)
+		}
+	}
+
+	SortFirewallRules(extra)
+	SortFirewallRules(missing)
+
+	return extra, missing
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/civo/civogo"
+)
+
+func TestSortFirewallRules(t *testing.T) {
+	rules := []civogo.FirewallRule{
+		{ID: "e", Direction: "ingress", Protocol: "tcp", StartPort: "443", Cidr: []string{"10.0.0.0/8", "0.0.0.0/0"}},
+		{ID: "d", Direction: "ingress", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.0.0/16"}},
+		{ID: "c", Direction: "ingress", Protocol: "tcp", StartPort: "80", Cidr: []string{"10.0.0.0/8"}},
+		{ID: "b", Direction: "ingress", Protocol: "icmp", Cidr: []string{"0.0.0.0/0"}},
+		{ID: "a", Direction: "egress", Protocol: "tcp", StartPort: "1", Cidr: []string{"0.0.0.0/0"}},
+	}
+
+	SortFirewallRules(rules)
+
+	var ids []string
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+
+	expected := []string{"a", "b", "c", "d", "e"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected the rules in the order %v, got %v", expected, ids)
+	}
+
+	if !reflect.DeepEqual(rules[4].Cidr, []string{"0.0.0.0/0", "10.0.0.0/8"}) {
+		t.Errorf("expected the cidr to be sorted, got %v", rules[4].Cidr)
+	}
+}
+
+func TestCidrContains(t *testing.T) {
+	cases := []struct {
+		outer, inner string
+		expected     bool
+	}{
+		{"192.168.1.0/24", "192.168.1.2/32", true},
+		{"192.168.1.0/24", "192.168.1.2", true},
+		{"0.0.0.0/0", "10.0.0.0/8", true},
+		{"192.168.1.0/24", "192.168.0.0/16", false},
+		{"192.168.1.0/24", "10.0.0.1/32", false},
+		{"2001:db8::/32", "2001:db8::1/128", true},
+		{"0.0.0.0/0", "2001:db8::/32", false},
+		{"not-a-cidr", "10.0.0.1/32", false},
+	}
+
+	for _, c := range cases {
+		if got := CidrContains(c.outer, c.inner); got != c.expected {
+			t.Errorf("CidrContains(%q, %q): expected %t, got %t", c.outer, c.inner, c.expected, got)
+		}
+	}
+}
+
+func TestFirewallRuleShadowedBy(t *testing.T) {
+	existing := civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", EndPort: "443", Cidr: []string{"192.168.1.0/24"}}
+
+	cases := []struct {
+		rule     civogo.FirewallRule
+		expected bool
+	}{
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.1.2/32"}}, true},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "22", Cidr: []string{"192.168.1.2/32"}}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.1.2/32", "10.0.0.1/32"}}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "deny", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.1.2/32"}}, false},
+		{civogo.FirewallRule{Direction: "egress", Action: "allow", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.1.2/32"}}, false},
+		{civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "udp", StartPort: "80", Cidr: []string{"192.168.1.2/32"}}, false},
+	}
+
+	for _, c := range cases {
+		if got := FirewallRuleShadowedBy(c.rule, existing); got != c.expected {
+			t.Errorf("FirewallRuleShadowedBy(%+v): expected %t, got %t", c.rule, c.expected, got)
+		}
+	}
+}
+
+func TestNormalizeFirewallRule(t *testing.T) {
+	rule := civogo.FirewallRule{ID: "a", Label: "web", Direction: "Ingress", Action: "ALLOW", Protocol: "TCP", StartPort: "80", Cidr: []string{"10.0.0.1", "0.0.0.0/0"}}
+
+	expected := civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", EndPort: "80", Cidr: []string{"0.0.0.0/0", "10.0.0.1/32"}}
+	if got := NormalizeFirewallRule(rule); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	icmp := civogo.FirewallRule{Direction: "ingress", Action: "allow", Protocol: "icmp", StartPort: "0", Cidr: []string{"0.0.0.0/0"}}
+	if got := NormalizeFirewallRule(icmp); got.StartPort != "" || got.EndPort != "" {
+		t.Errorf("expected the icmp rule to have no ports, got %s-%s", got.StartPort, got.EndPort)
+	}
+}
+
+func TestDiffFirewallRules(t *testing.T) {
+	live := []civogo.FirewallRule{
+		{ID: "a", Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "22", EndPort: "22", Cidr: []string{"0.0.0.0/0"}},
+		{ID: "b", Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", Cidr: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+		{ID: "c", Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", Cidr: []string{"192.168.0.0/16", "10.0.0.0/8"}},
+	}
+	expected := []civogo.FirewallRule{
+		{Label: "ssh", Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "22", Cidr: []string{"0.0.0.0/0"}},
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "80", EndPort: "80", Cidr: []string{"192.168.0.0/16", "10.0.0.0/8"}},
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", StartPort: "443", Cidr: []string{"0.0.0.0/0"}},
+	}
+
+	extra, missing := DiffFirewallRules(live, expected)
+	if len(extra) != 1 || extra[0].ID != "c" {
+		t.Errorf("expected the duplicated rule c to be extra, got %+v", extra)
+	}
+	if len(missing) != 1 || missing[0].StartPort != "443" {
+		t.Errorf("expected the rule for 443 to be missing, got %+v", missing)
+	}
+
+	extra, missing = DiffFirewallRules(live[:2], expected[:2])
+	if len(extra) != 0 || len(missing) != 0 {
+		t.Errorf("expected the rules to be in sync, got extra %+v and missing %+v", extra, missing)
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/civo/civogo"
+)
+
+// civogo v0.2.74 doesn't have the reserved IP API and the module can't be
+// updated yet, so the calls to the /v2/ips endpoints are all in this file.
+// Replace them with the civogo functions when it is updated
+
+// ReservedIP is a reserved IP returned by the API
+type ReservedIP struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IP         string `json:"ip"`
+	AssignedTo struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"assigned_to"`
+}
+
+// reservedIPsPerPage is the number of reserved IPs read in every page
+const reservedIPsPerPage = 100
+
+// ListReservedIPs list all the reserved IPs in the region of the client, the
+// API returns them in pages so every page is read until the last one
+func ListReservedIPs(client *civogo.Client) ([]ReservedIP, error) {
+	all := make([]ReservedIP, 0)
+	for page := 1; ; page++ {
+		resp, err := client.SendGetRequest(fmt.Sprintf("/v2/ips?page=%d&per_page=%d", page, reservedIPsPerPage))
+		if err != nil {
+			return nil, err
+		}
+
+		ips := struct {
+			Page  int          `json:"page"`
+			Pages int          `json:"pages"`
+			Items []ReservedIP `json:"items"`
+		}{}
+		if err := json.NewDecoder(bytes.NewReader(resp)).Decode(&ips); err != nil {
+			return nil, err
+		}
+
+		all = append(all, ips.Items...)
+		if len(ips.Items) == 0 || page >= ips.Pages {
+			return all, nil
+		}
+	}
+}
+
+// FindReservedIP search the reserved IP by ID, name or address, it returns
+// nil if there is no reserved IP with that value
+func FindReservedIP(client *civogo.Client, search string) (*ReservedIP, error) {
+	ips, err := ListReservedIPs(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		if ip.ID == search || ip.Name == search || ip.IP == search {
+			return &ip, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// reservedIPAction is the request to assign or unassign a reserved IP
+type reservedIPAction struct {
+	Action       string `json:"action"`
+	AssignToID   string `json:"assign_to_id,omitempty"`
+	AssignToType string `json:"assign_to_type,omitempty"`
+	Region       string `json:"region"`
+}
+
+// AssignReservedIP assign the reserved IP with the ID to the instance
+func AssignReservedIP(client *civogo.Client, id string, instanceID string) error {
+	_, err := client.SendPostRequest(fmt.Sprintf("/v2/ips/%s/actions", id), &reservedIPAction{
+		Action:       "assign",
+		AssignToID:   instanceID,
+		AssignToType: "instance",
+		Region:       client.Region,
+	})
+	return err
+}
+
+// UnassignReservedIP unassign the reserved IP with the ID
+func UnassignReservedIP(client *civogo.Client, id string) error {
+	_, err := client.SendPostRequest(fmt.Sprintf("/v2/ips/%s/actions", id), &reservedIPAction{
+		Action: "unassign",
+		Region: client.Region,
+	})
+	return err
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/civo/civogo"
+)
+
+func TestListReservedIPs(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet || req.URL.Path != "/v2/ips" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		page := req.URL.Query().Get("page")
+		pages = append(pages, page)
+		switch page {
+		case "1":
+			fmt.Fprint(rw, `{"page": 1, "per_page": 1, "pages": 2, "items": [{"id": "1", "name": "web", "ip": "192.0.2.1", "assigned_to": {"id": "12345", "type": "instance"}}]}`)
+		case "2":
+			fmt.Fprint(rw, `{"page": 2, "per_page": 1, "pages": 2, "items": [{"id": "2", "name": "db", "ip": "192.0.2.2"}]}`)
+		default:
+			fmt.Fprint(rw, `{"page": 3, "per_page": 1, "pages": 2, "items": []}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ips, err := ListReservedIPs(client)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ips) != 2 || ips[1].ID != "2" {
+		t.Fatalf("expected the reserved ips of both pages, got %v", ips)
+	}
+	if ips[0].AssignedTo.ID != "12345" || ips[0].AssignedTo.Type != "instance" {
+		t.Fatalf("expected the reserved ip 1 to be assigned to the instance 12345, got %v", ips[0].AssignedTo)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages to be read, got %v", pages)
+	}
+}
+
+func TestFindReservedIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, `{"page": 1, "per_page": 100, "pages": 1, "items": [{"id": "1", "name": "web", "ip": "192.0.2.1"}, {"id": "2", "name": "db", "ip": "192.0.2.2"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, search := range []string{"2", "db", "192.0.2.2"} {
+		ip, err := FindReservedIP(client, search)
+		if err != nil || ip == nil || ip.ID != "2" {
+			t.Fatalf("expected the reserved ip 2 for %s, got %v, %v", search, ip, err)
+		}
+	}
+
+	ip, err := FindReservedIP(client, "missing")
+	if err != nil || ip != nil {
+		t.Fatalf("expected no reserved ip, got %v, %v", ip, err)
+	}
+}
+
+func TestAssignReservedIP(t *testing.T) {
+	var actions []reservedIPAction
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.URL.Path != "/v2/ips/1/actions" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		action := reservedIPAction{}
+		if err := json.NewDecoder(req.Body).Decode(&action); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		actions = append(actions, action)
+		fmt.Fprint(rw, `{"result": "success"}`)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	if err := AssignReservedIP(client, "1", "12345"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := UnassignReservedIP(client, "1"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []reservedIPAction{
+		{Action: "assign", AssignToID: "12345", AssignToType: "instance", Region: "LON1"},
+		{Action: "unassign", Region: "LON1"},
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("expected the actions %v, got %v", expected, actions)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Fatalf("expected the action %v, got %v", expected[i], actions[i])
+		}
+	}
+
+	if err := AssignReservedIP(client, "missing", "12345"); err == nil {
+		t.Fatal("expected an error when the API rejects the action")
+	}
+}
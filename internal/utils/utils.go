@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func ValidateName(v interface{}, k string) (ws []string, es []error) {
@@ -72,6 +73,61 @@ func ValidateNameSize(v interface{}, k string) (ws []string, es []error) {
 	return warns, errs
 }
 
+// ValidateSingleID check the value is one ID, not a list of IDs
+// joined with commas or spaces, like "id1,id2"
+func ValidateSingleID(v interface{}, k string) (ws []string, es []error) {
+	var errs []error
+	var warns []string
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %s to be string", k))
+		return warns, errs
+	}
+
+	if value == "" {
+		errs = append(errs, fmt.Errorf("%s cannot be empty", k))
+		return warns, errs
+	}
+
+	if strings.ContainsAny(value, ", ;\t\n[]") {
+		errs = append(errs, fmt.Errorf("%s has to be a single ID, not a list. Got %s", k, value))
+		return warns, errs
+	}
+
+	return warns, errs
+}
+
+// hostnameLabelRegexp match a label of a hostname, letters, digits and hyphens
+// without a hyphen at the start or the end
+var hostnameLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateHostname check the value is a well-formed hostname, like the
+// reverse DNS of an instance, a trailing dot is accepted
+func ValidateHostname(v interface{}, k string) (ws []string, es []error) {
+	var errs []error
+	var warns []string
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %s to be string", k))
+		return warns, errs
+	}
+
+	hostname := strings.TrimSuffix(value, ".")
+	if hostname == "" || len(hostname) > 253 {
+		errs = append(errs, fmt.Errorf("%s has to be a hostname of 1 to 253 characters. Got %q", k, value))
+		return warns, errs
+	}
+
+	for _, label := range strings.Split(hostname, ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			errs = append(errs, fmt.Errorf("%s has to be a well-formed hostname, the label %q is not valid. Got %s", k, label, value))
+			return warns, errs
+		}
+	}
+
+	return warns, errs
+}
+
 // util function to help the import function
 func ResourceCommonParseID(id string) (string, string, error) {
 	parts := strings.SplitN(id, ":", 2)
@@ -108,3 +164,101 @@ func GetCommaSeparatedAllowedKeys(allowedKeys []string) string {
 	sort.Strings(res)
 	return strings.Join(res, ", ")
 }
+
+// NormalizeRegion returns the region code in the same format the Civo API uses
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+// DiffSuppressRegion is used to ignore the case of the region code, "lon1" and "LON1" are the same region
+func DiffSuppressRegion(k, old, new string, d *schema.ResourceData) bool {
+	return NormalizeRegion(old) == NormalizeRegion(new)
+}
+
+// normalizeTag returns the tag used to compare the tags, in lower case if the case is ignored
+func normalizeTag(tag string, ignoreCase bool) string {
+	tag = strings.TrimSpace(tag)
+	if ignoreCase {
+		return strings.ToLower(tag)
+	}
+	return tag
+}
+
+// HashTag is the hash of the tag sets, with ignoreCase "Web" and "web" are the
+// same tag. The SDK only compares the hashes of a set, so a change of case is
+// never a diff then, use DiffSuppressTagSet to choose it in the resource
+func HashTag(ignoreCase bool) schema.SchemaSetFunc {
+	return func(v interface{}) int {
+		return schema.HashString(normalizeTag(v.(string), ignoreCase))
+	}
+}
+
+// DiffSuppressTags is used for the tags in a space separated string, the
+// order of the tags is ignored and the case too with ignoreCase
+func DiffSuppressTags(ignoreCase bool) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return TagsEqual(strings.Fields(old), strings.Fields(new), ignoreCase)
+	}
+}
+
+// DiffSuppressTagSet is used for the tag sets hashed with HashTag(false), the
+// whole set is compared ignoring the case when the bool attribute
+// ignoreCaseKey of the resource is true
+func DiffSuppressTagSet(ignoreCaseKey string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if !d.Get(ignoreCaseKey).(bool) {
+			return false
+		}
+
+		// k is the count or an element of the set, we compare the whole set
+		oldTags, newTags := d.GetChange(strings.SplitN(k, ".", 2)[0])
+		return TagsEqual(setStrings(oldTags.(*schema.Set)), setStrings(newTags.(*schema.Set)), true)
+	}
+}
+
+// TagsEqual compare two lists of tags, the order of the tags is ignored and
+// the case too with ignoreCase
+func TagsEqual(a []string, b []string, ignoreCase bool) bool {
+	return strings.Join(normalizeTags(a, ignoreCase), " ") == strings.Join(normalizeTags(b, ignoreCase), " ")
+}
+
+// setStrings returns the strings of a set
+func setStrings(set *schema.Set) []string {
+	list := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		list = append(list, v.(string))
+	}
+	return list
+}
+
+// DiffSuppressTagsIgnoreCaseFrom is DiffSuppressTags with the case ignored
+// only when the bool attribute ignoreCaseKey of the resource is true, so the
+// users can choose it in every resource
+func DiffSuppressTagsIgnoreCaseFrom(ignoreCaseKey string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return DiffSuppressTags(d.Get(ignoreCaseKey).(bool))(k, old, new, d)
+	}
+}
+
+// normalizeTags returns the sorted and normalized tags, without the empty ones
+func normalizeTags(tags []string, ignoreCase bool) []string {
+	fields := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag, ignoreCase); tag != "" {
+			fields = append(fields, tag)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// RegionScopedClient returns a copy of the client that uses the region, so the
+// region of the shared client is not changed for the other resources. If the
+// region is empty the copy uses the region of the client
+func RegionScopedClient(client *civogo.Client, region string) *civogo.Client {
+	scoped := *client
+	if region != "" {
+		scoped.Region = region
+	}
+	return &scoped
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDiffSuppressRegion(t *testing.T) {
+	cases := []struct {
+		old, new string
+		suppress bool
+	}{
+		{"LON1", "LON1", true},
+		{"LON1", "lon1", true},
+		{"lon1 ", "LON1", true},
+		{"LON1", "NYC1", false},
+		{"LON1", "", false},
+	}
+
+	for _, c := range cases {
+		if got := DiffSuppressRegion("region", c.old, c.new, nil); got != c.suppress {
+			t.Errorf("DiffSuppressRegion(%q, %q): expected %t, got %t", c.old, c.new, c.suppress, got)
+		}
+	}
+}
+
+func TestRegionScopedClient(t *testing.T) {
+	client := &civogo.Client{APIKey: "token", Region: "LON1"}
+
+	scoped := RegionScopedClient(client, "NYC1")
+	if scoped.Region != "NYC1" {
+		t.Errorf("expected the scoped client to use NYC1, got %s", scoped.Region)
+	}
+	if scoped.APIKey != "token" {
+		t.Errorf("expected the scoped client to keep the token, got %s", scoped.APIKey)
+	}
+	if client.Region != "LON1" {
+		t.Errorf("expected the client to keep LON1, got %s", client.Region)
+	}
+
+	if scoped := RegionScopedClient(client, ""); scoped.Region != "LON1" {
+		t.Errorf("expected the scoped client to fall back to LON1, got %s", scoped.Region)
+	}
+}
+
+func TestValidateSingleID(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{"9c2b5d6e-1a2b-4c3d-8e9f-0a1b2c3d4e5f", true},
+		{"", false},
+		{"net-1,net-2", false},
+		{"net-1 net-2", false},
+		{`["net-1"]`, false},
+	}
+
+	for _, c := range cases {
+		_, errs := ValidateSingleID(c.value, "network_id")
+		if valid := len(errs) == 0; valid != c.valid {
+			t.Errorf("ValidateSingleID(%q): expected valid %t, got %v", c.value, c.valid, errs)
+		}
+	}
+}
+
+func TestHashTag(t *testing.T) {
+	for _, ignoreCase := range []bool{true, false} {
+		r := &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"tags": {
+					Type:     schema.TypeSet,
+					Optional: true,
+					Set:      HashTag(ignoreCase),
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						DiffSuppressFunc: DiffSuppressTags(ignoreCase),
+					},
+				},
+			},
+		}
+
+		d := r.TestResourceData()
+		d.SetId("12345")
+		d.Set("tags", []interface{}{"prod", "web"})
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"tags": []interface{}{"Web", "prod"},
+		})
+		diff, err := r.Diff(context.Background(), d.State(), config, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if empty := diff == nil || diff.Empty(); empty != ignoreCase {
+			t.Errorf("HashTag(%t): expected an empty diff %t for the reordered and re-cased tags, got %v", ignoreCase, ignoreCase, diff)
+		}
+	}
+}
+
+func TestDiffSuppressTags(t *testing.T) {
+	cases := []struct {
+		old, new   string
+		ignoreCase bool
+		suppress   bool
+	}{
+		{"web prod", "prod web", true, true},
+		{"web prod", "Prod  WEB", true, true},
+		{" web  prod ", "prod web", false, true},
+		{"web prod", "Prod web", false, false},
+		{"web prod", "prod web", false, true},
+		{"web prod", "web", true, false},
+		{"", "web", true, false},
+	}
+
+	for _, c := range cases {
+		if got := DiffSuppressTags(c.ignoreCase)("tags", c.old, c.new, nil); got != c.suppress {
+			t.Errorf("DiffSuppressTags(%t)(%q, %q): expected %t, got %t", c.ignoreCase, c.old, c.new, c.suppress, got)
+		}
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{"mail.example.com", true},
+		{"mail.example.com.", true},
+		{"mx-1.example.com", true},
+		{"", false},
+		{"mail..example.com", false},
+		{"-mail.example.com", false},
+		{"mail_1.example.com", false},
+		{"mail example.com", false},
+	}
+
+	for _, c := range cases {
+		_, errs := ValidateHostname(c.value, "reverse_dns")
+		if valid := len(errs) == 0; valid != c.valid {
+			t.Errorf("ValidateHostname(%q): expected valid %t, got %v", c.value, c.valid, errs)
+		}
+	}
+}
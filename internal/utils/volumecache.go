@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+// volumeCacheKey identify the volumes of an account in a region, the client
+// itself is not part of the key because the resources change its region
+type volumeCacheKey struct {
+	apiKey  string
+	baseURL string
+	region  string
+}
+
+// volumeCacheEntry is the list of volumes of a region, the lock is held while
+// the list is read from the API, so the concurrent reads wait for it instead
+// of listing the volumes again
+type volumeCacheEntry struct {
+	lock    sync.Mutex
+	volumes []civogo.Volume
+	expires time.Time
+}
+
+// VolumeCache keep the list of volumes read from the API for a short time, so
+// the reads of many resources in the same refresh list the volumes only once.
+// The Civo API has no call to get many volumes by ID, and civogo lists all the
+// volumes of the region to find a single one, so the cache keeps the full list
+type VolumeCache struct {
+	lock  sync.Mutex
+	ttl   time.Duration
+	now   func() time.Time
+	store map[volumeCacheKey]*volumeCacheEntry
+}
+
+// NewVolumeCache returns an empty cache that keeps the volumes for the ttl
+func NewVolumeCache(ttl time.Duration) *VolumeCache {
+	return &VolumeCache{
+		ttl:   ttl,
+		now:   time.Now,
+		store: make(map[volumeCacheKey]*volumeCacheEntry),
+	}
+}
+
+// FindVolume returns the volume with the ID from the volumes of the region of
+// the client, the volumes are listed again if they are older than the ttl
+func (c *VolumeCache) FindVolume(client *civogo.Client, id string) (*civogo.Volume, error) {
+	entry := c.get(client)
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if entry.volumes == nil || !c.now().Before(entry.expires) {
+		volumes, err := client.ListVolumes()
+		if err != nil {
+			return nil, err
+		}
+		entry.volumes = volumes
+		entry.expires = c.now().Add(c.ttl)
+	}
+
+	for _, volume := range entry.volumes {
+		if volume.ID == id {
+			found := volume
+			return &found, nil
+		}
+	}
+
+	// the same error as civogo, so IsNotFoundError matches it
+	return nil, fmt.Errorf("%w: unable to find %s, zero matches", civogo.ZeroMatchesError, id)
+}
+
+// Invalidate drop the volumes of the region of the client, it must be called
+// after a change to the volumes, so the next read gets them from the API
+func (c *VolumeCache) Invalidate(client *civogo.Client) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.store, newVolumeCacheKey(client))
+}
+
+// get returns the entry for the region of the client, creating it if needed
+func (c *VolumeCache) get(client *civogo.Client) *volumeCacheEntry {
+	key := newVolumeCacheKey(client)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.store[key]
+	if !ok {
+		entry = &volumeCacheEntry{}
+		c.store[key] = entry
+	}
+	return entry
+}
+
+func newVolumeCacheKey(client *civogo.Client) volumeCacheKey {
+	key := volumeCacheKey{apiKey: client.APIKey, region: NormalizeRegion(client.Region)}
+	if client.BaseURL != nil {
+		key.baseURL = client.BaseURL.String()
+	}
+	return key
+}
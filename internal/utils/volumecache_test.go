@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+func TestVolumeCache(t *testing.T) {
+	listed := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v2/volumes" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		region := req.URL.Query().Get("region")
+		listed[region]++
+		fmt.Fprintf(rw, `[{"id": "vol-1", "name": "data-%s", "instance_id": "ins-1"}, {"id": "vol-2", "name": "logs"}]`, region)
+	}))
+	defer server.Close()
+
+	client, err := civogo.NewClientForTestingWithServer(server)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Region = "LON1"
+
+	now := time.Now()
+	cache := NewVolumeCache(30 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	for _, id := range []string{"vol-1", "vol-2", "vol-1"} {
+		volume, err := cache.FindVolume(client, id)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if volume.ID != id {
+			t.Fatalf("expected the volume %s, got %s", id, volume.ID)
+		}
+	}
+	if listed["LON1"] != 1 {
+		t.Fatalf("expected the volumes to be listed once, got %d", listed["LON1"])
+	}
+
+	if volume, err := cache.FindVolume(client, "missing"); !IsNotFoundError(err) || volume != nil {
+		t.Fatalf("expected a not found error for a volume that doesn't exist, got %v (%v)", volume, err)
+	}
+
+	// the volumes of another region are listed on their own
+	other := *client
+	other.Region = "NYC1"
+	if volume, err := cache.FindVolume(&other, "vol-1"); err != nil || volume.Name != "data-NYC1" {
+		t.Fatalf("expected the volume of NYC1, got %v (%v)", volume, err)
+	}
+	if listed["NYC1"] != 1 || listed["LON1"] != 1 {
+		t.Fatalf("expected each region to be listed once, got %v", listed)
+	}
+
+	cache.Invalidate(client)
+	if _, err := cache.FindVolume(client, "vol-1"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if listed["LON1"] != 2 {
+		t.Fatalf("expected the volumes to be listed again after the invalidation, got %d", listed["LON1"])
+	}
+
+	now = now.Add(31 * time.Second)
+	if _, err := cache.FindVolume(client, "vol-1"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if listed["LON1"] != 3 {
+		t.Fatalf("expected the volumes to be listed again after the ttl, got %d", listed["LON1"])
+	}
+}